@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/config3"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/erigon-lib/kv/temporal"
+	libstate "github.com/ledgerwatch/erigon-lib/state"
+
+	"github.com/ledgerwatch/erigon/cmd/utils"
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/ethconsensusconfig"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/logging"
+	"github.com/ledgerwatch/erigon/turbo/replay"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+const executorUsage = "`local[:opt,...]` - execute in-process on --datadir (opts: skip-analysis, eip=N), or URL of a node's JSON-RPC (needs debug namespace)"
+
+var (
+	aFlag = cli.StringFlag{
+		Name:     "a",
+		Usage:    "First executor: " + executorUsage,
+		Required: true,
+	}
+	bFlag = cli.StringFlag{
+		Name:     "b",
+		Usage:    "Second executor: " + executorUsage,
+		Required: true,
+	}
+	fromFlag = cli.Uint64Flag{
+		Name:     "from",
+		Usage:    "First block to replay",
+		Required: true,
+	}
+	toFlag = cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "Last block to replay (inclusive)",
+		Required: true,
+	}
+	maxDivergencesFlag = cli.Uint64Flag{
+		Name:  "max-divergences",
+		Usage: "Stop after this amount of divergences, 0 - replay whole range",
+	}
+)
+
+var errMaxDivergences = errors.New("max divergences reached")
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "evmreplay"
+	app.Version = params.VersionWithCommit(params.GitCommit)
+	app.Usage = "Replays historical transactions by 2 EVMs and reports divergences in gas, logs and state changes (as json lines to stdout)"
+	app.UsageText = `evmreplay --datadir=<dir> --a=local --b=local:skip-analysis --from=<block> --to=<block>
+   evmreplay --a=http://localhost:8545 --b=http://localhost:8546 --from=<block> --to=<block>`
+	app.Flags = append([]cli.Flag{&utils.DataDirFlag, &aFlag, &bFlag, &fromFlag, &toFlag, &maxDivergencesFlag}, logging.Flags...)
+	app.Action = replayBlocks
+
+	ctx, cancel := common.RootContext()
+	err := app.RunContext(ctx, os.Args)
+	cancel()
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func replayBlocks(cliCtx *cli.Context) error {
+	logger := logging.SetupLoggerCtx("evmreplay", cliCtx, log.LvlInfo, log.LvlInfo, true)
+	ctx := cliCtx.Context
+
+	var local *localDB // shared by local executors
+	var clients []*rpc.Client
+	defer func() {
+		if local != nil {
+			local.Close()
+		}
+		for _, client := range clients {
+			client.Close()
+		}
+	}()
+	newExecutor := func(spec string) (replay.Executor, error) {
+		if spec != "local" && !strings.HasPrefix(spec, "local:") {
+			client, err := rpc.DialContext(ctx, spec, logger)
+			if err != nil {
+				return nil, err
+			}
+			clients = append(clients, client)
+			return replay.NewRpcExecutor(client), nil
+		}
+		vmConfig, err := parseVMConfig(strings.TrimPrefix(strings.TrimPrefix(spec, "local"), ":"))
+		if err != nil {
+			return nil, err
+		}
+		if local == nil {
+			if local, err = openLocalDB(ctx, datadir.New(cliCtx.String(utils.DataDirFlag.Name)), logger); err != nil {
+				return nil, err
+			}
+		}
+		return replay.NewLocalExecutor(local.db, local.blockReader, local.engine, local.chainConfig, vmConfig), nil
+	}
+
+	a, err := newExecutor(cliCtx.String(aFlag.Name))
+	if err != nil {
+		return fmt.Errorf("--%s: %w", aFlag.Name, err)
+	}
+	b, err := newExecutor(cliCtx.String(bFlag.Name))
+	if err != nil {
+		return fmt.Errorf("--%s: %w", bFlag.Name, err)
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	maxDivergences := cliCtx.Uint64(maxDivergencesFlag.Name)
+	var divergences uint64
+	stats, err := replay.Run(ctx, a, b, cliCtx.Uint64(fromFlag.Name), cliCtx.Uint64(toFlag.Name), func(d replay.Divergence) error {
+		if err := out.Encode(d); err != nil {
+			return err
+		}
+		divergences++
+		if maxDivergences > 0 && divergences >= maxDivergences {
+			return errMaxDivergences
+		}
+		return nil
+	}, logger)
+	if err != nil && !errors.Is(err, errMaxDivergences) {
+		return err
+	}
+	logger.Info("[replay] done", "blocks", stats.Blocks, "txs", stats.Txs, "diverged", stats.DivergedTxs)
+	if stats.DivergedTxs > 0 {
+		return fmt.Errorf("%d of %d transactions diverged", stats.DivergedTxs, stats.Txs)
+	}
+	return nil
+}
+
+// parseVMConfig - comma-separated options of a local executor
+func parseVMConfig(opts string) (vm.Config, error) {
+	var cfg vm.Config
+	for _, opt := range strings.Split(opts, ",") {
+		switch {
+		case opt == "":
+		case opt == "skip-analysis":
+			cfg.SkipAnalysis = true
+		case strings.HasPrefix(opt, "eip="):
+			eip, err := strconv.Atoi(strings.TrimPrefix(opt, "eip="))
+			if err != nil {
+				return cfg, fmt.Errorf("invalid option %q: %w", opt, err)
+			}
+			cfg.ExtraEips = append(cfg.ExtraEips, eip)
+		default:
+			return cfg, fmt.Errorf("unknown option %q", opt)
+		}
+	}
+	return cfg, nil
+}
+
+type localDB struct {
+	db           kv.RwDB
+	agg          *libstate.Aggregator
+	snapshots    *freezeblocks.RoSnapshots
+	borSnapshots *freezeblocks.BorRoSnapshots
+	blockReader  services.FullBlockReader
+	chainConfig  *chain.Config
+	engine       consensus.Engine
+}
+
+// openLocalDB opens datadir of a stopped (or running) Erigon for reading history
+func openLocalDB(ctx context.Context, dirs datadir.Dirs, logger log.Logger) (*localDB, error) {
+	rawDB, err := mdbx.NewMDBX(logger).Path(dirs.Chaindata).Accede().Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var chainConfig *chain.Config
+	if err := rawDB.View(ctx, func(tx kv.Tx) error {
+		genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+		if err != nil {
+			return err
+		}
+		chainConfig, err = rawdb.ReadChainConfig(tx, genesisHash)
+		return err
+	}); err != nil {
+		rawDB.Close()
+		return nil, err
+	}
+	if chainConfig == nil {
+		rawDB.Close()
+		return nil, fmt.Errorf("chain config not found in db: %s", dirs.Chaindata)
+	}
+	// engine provides block author and system calls. Clique, AuRa and Bor engines open own db in datadir
+	// (which may be in use by a running node) - not supported
+	if chainConfig.Clique != nil || chainConfig.Aura != nil || chainConfig.Bor != nil {
+		rawDB.Close()
+		return nil, fmt.Errorf("local executor supports only ethash and proof-of-stake chains, not %s", chainConfig.ChainName)
+	}
+	engine := ethconsensusconfig.CreateConsensusEngineBareBones(ctx, chainConfig, logger)
+
+	snapCfg := ethconfig.BlocksFreezing{Enabled: true}
+	snapshots := freezeblocks.NewRoSnapshots(snapCfg, dirs.Snap, 0, logger)
+	borSnapshots := freezeblocks.NewBorRoSnapshots(snapCfg, dirs.Snap, 0, logger)
+	snapshots.OptimisticReopenWithDB(rawDB)
+	borSnapshots.OptimisticalyReopenWithDB(rawDB)
+
+	agg, err := libstate.NewAggregator(ctx, dirs, config3.HistoryV3AggregationStep, rawDB, logger)
+	if err != nil {
+		snapshots.Close()
+		borSnapshots.Close()
+		rawDB.Close()
+		engine.Close()
+		return nil, fmt.Errorf("create aggregator: %w", err)
+	}
+	if err := agg.OpenFolder(true); err != nil {
+		agg.Close()
+		snapshots.Close()
+		borSnapshots.Close()
+		rawDB.Close()
+		engine.Close()
+		return nil, err
+	}
+	db, err := temporal.New(rawDB, agg)
+	if err != nil {
+		agg.Close()
+		snapshots.Close()
+		borSnapshots.Close()
+		rawDB.Close()
+		engine.Close()
+		return nil, err
+	}
+	return &localDB{
+		db:           db,
+		agg:          agg,
+		snapshots:    snapshots,
+		borSnapshots: borSnapshots,
+		blockReader:  freezeblocks.NewBlockReader(snapshots, borSnapshots),
+		chainConfig:  chainConfig,
+		engine:       engine,
+	}, nil
+}
+
+func (l *localDB) Close() {
+	l.db.Close()
+	l.agg.Close()
+	l.snapshots.Close()
+	l.borSnapshots.Close()
+	l.engine.Close()
+}
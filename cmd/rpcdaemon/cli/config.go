@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ledgerwatch/log/v3"
@@ -144,6 +145,7 @@ func RootCommand() (*cobra.Command, *httpcfg.HttpCfg) {
 	rootCmd.PersistentFlags().IntVar(&cfg.MaxGetProofRewindBlockCount, utils.RpcMaxGetProofRewindBlockCount.Name, utils.RpcMaxGetProofRewindBlockCount.Value, utils.RpcMaxGetProofRewindBlockCount.Usage)
 	rootCmd.PersistentFlags().Uint64Var(&cfg.OtsMaxPageSize, utils.OtsSearchMaxCapFlag.Name, utils.OtsSearchMaxCapFlag.Value, utils.OtsSearchMaxCapFlag.Usage)
 	rootCmd.PersistentFlags().DurationVar(&cfg.RPCSlowLogThreshold, utils.RPCSlowFlag.Name, utils.RPCSlowFlag.Value, utils.RPCSlowFlag.Usage)
+	rootCmd.PersistentFlags().DurationVar(&cfg.ShutdownDrain, utils.RpcShutdownDrainFlag.Name, utils.RpcShutdownDrainFlag.Value, utils.RpcShutdownDrainFlag.Usage)
 	rootCmd.PersistentFlags().IntVar(&cfg.WebsocketSubscribeLogsChannelSize, utils.WSSubscribeLogsChannelSize.Name, utils.WSSubscribeLogsChannelSize.Value, utils.WSSubscribeLogsChannelSize.Usage)
 
 	if err := rootCmd.MarkPersistentFlagFilename("rpc.accessList", "json"); err != nil {
@@ -569,7 +571,23 @@ func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []r
 
 	srv.SetBatchLimit(cfg.BatchLimit)
 
-	defer srv.Stop()
+	// Graceful shutdown: listeners stop accepting connections while the server drains in-flight requests,
+	// only then the function returns and the caller may close the db
+	var listeners []func(ctx context.Context)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownDrain)
+		defer cancel()
+		var wg sync.WaitGroup
+		for _, shutdown := range listeners {
+			wg.Add(1)
+			go func(shutdown func(ctx context.Context)) {
+				defer wg.Done()
+				shutdown(shutdownCtx)
+			}(shutdown)
+		}
+		srv.Shutdown(shutdownCtx, "server is shutting down")
+		wg.Wait()
+	}()
 
 	var defaultAPIList []rpc.API
 
@@ -641,12 +659,10 @@ func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []r
 			return fmt.Errorf("could not start separate Websocket RPC api at port %d: %w", cfg.WebsocketPort, err)
 		}
 		info = append(info, "websocket.url", wsAddr)
-		defer func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_ = wsListener.Shutdown(shutdownCtx)
+		listeners = append(listeners, func(ctx context.Context) {
+			_ = wsListener.Shutdown(ctx)
 			logger.Info("HTTP endpoint closed", "url", wsAddr)
-		}()
+		})
 	}
 
 	if cfg.HttpServerEnabled {
@@ -661,12 +677,10 @@ func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []r
 			return fmt.Errorf("could not start RPC api: %w", err)
 		}
 		info = append(info, "http.url", httpAddr)
-		defer func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_ = listener.Shutdown(shutdownCtx)
+		listeners = append(listeners, func(ctx context.Context) {
+			_ = listener.Shutdown(ctx)
 			logger.Info("HTTP endpoint closed", "url", httpAddr)
-		}()
+		})
 	}
 	if cfg.HttpsURL != "" {
 		cfg.HttpsServerEnabled = true
@@ -689,12 +703,10 @@ func startRegularRpcServer(ctx context.Context, cfg *httpcfg.HttpCfg, rpcAPI []r
 			return fmt.Errorf("could not start RPC api: %w", err)
 		}
 		info = append(info, "https.url", httpAddr)
-		defer func() {
-			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-			_ = listener.Shutdown(shutdownCtx)
+		listeners = append(listeners, func(ctx context.Context) {
+			_ = listener.Shutdown(ctx)
 			logger.Info("HTTPS endpoint closed", "url", httpAddr)
-		}()
+		})
 	}
 
 	var (
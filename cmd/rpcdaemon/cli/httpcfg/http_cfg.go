@@ -50,6 +50,7 @@ type HttpCfg struct {
 	RpcAllowListFilePath              string
 	RpcBatchConcurrency               uint
 	RpcStreamingDisable               bool
+	RpcIndexRepair                    bool // Re-generate missing senders/txLookup entries found by RPC, needs write access to the db
 	DBReadConcurrency                 int
 	TraceCompatibility                bool // Bug for bug compatibility for trace_ routines with OpenEthereum
 	TxPoolApiAddr                     string
@@ -87,4 +88,5 @@ type HttpCfg struct {
 	OtsMaxPageSize uint64
 
 	RPCSlowLogThreshold time.Duration
+	ShutdownDrain       time.Duration // time given to in-flight requests to complete on shutdown
 }
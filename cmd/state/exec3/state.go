@@ -239,7 +239,12 @@ func (rw *Worker) RunTxTaskNoLock(txTask *state.TxTask) {
 		}
 
 		// MA applytx
-		applyRes, err := core.ApplyMessage(rw.evm, msg, rw.taskGasPool, true /* refunds */, false /* gasBailout */)
+		var applyRes *core.ExecutionResult
+		if aaTx, ok := txTask.Tx.(*types.AccountAbstractionTransaction); ok {
+			applyRes, err = core.ApplyAATransaction(rw.evm, aaTx, rw.taskGasPool, true /* refunds */)
+		} else {
+			applyRes, err = core.ApplyMessage(rw.evm, msg, rw.taskGasPool, true /* refunds */, false /* gasBailout */)
+		}
 		if err != nil {
 			txTask.Error = err
 		} else {
@@ -175,6 +175,11 @@ var (
 		Usage: "Price bump percentage to replace existing (type-3) blob transaction",
 		Value: txpoolcfg.DefaultConfig.BlobPriceBump,
 	}
+	TxPoolAccountAbstractionFlag = cli.BoolFlag{
+		Name:  "txpool.rip7560",
+		Usage: "Experimental: accept RIP-7560 native account abstraction transactions (type-5), chain config must set rip7560Time",
+		Value: txpoolcfg.DefaultConfig.AccountAbstraction,
+	}
 	TxPoolAccountSlotsFlag = cli.Uint64Flag{
 		Name:  "txpool.accountslots",
 		Usage: "Minimum number of executable transaction slots guaranteed per account",
@@ -387,6 +392,10 @@ var (
 		Name:  "rpc.streaming.disable",
 		Usage: "Erigon has enabled json streaming for some heavy endpoints (like trace_*). It's a trade-off: greatly reduce amount of RAM (in some cases from 30GB to 30mb), but it produce invalid json format if error happened in the middle of streaming (because json is not streaming-friendly format)",
 	}
+	RpcIndexRepairFlag = cli.BoolFlag{
+		Name:  "rpc.index.repair",
+		Usage: "Re-generate in background senders and txLookup entries of canonical blocks which RPC found missing. Works only if RPC is served by Erigon process (not by separated rpcdaemon)",
+	}
 	RpcBatchLimit = cli.IntFlag{
 		Name:  "rpc.batch.limit",
 		Usage: "Maximum number of requests in a batch",
@@ -757,6 +766,12 @@ var (
 		Value: (12 * datasize.TB).String(),
 	}
 
+	DatadirMaxSizeFlag = cli.StringFlag{
+		Name:  "datadir.max-size",
+		Usage: "Soft limit of the datadir size (e.g. 2TB). When approaching it: snapshot downloads are paused and pruning is prioritized, when exceeded: new snapshot files are not produced. Can be changed at runtime by admin_setDatadirMaxSize. Empty - no limit",
+		Value: "",
+	}
+
 	HealthCheckFlag = cli.BoolFlag{
 		Name:  "healthcheck",
 		Usage: "Enabling grpc health check",
@@ -958,6 +973,11 @@ var (
 		Usage: "Print in logs RPC requests slower than given threshold: 100ms, 1s, 1m. Exluded methods: " + strings.Join(rpccfg.SlowLogBlackList, ","),
 		Value: 0,
 	}
+	RpcShutdownDrainFlag = cli.DurationFlag{
+		Name:  "rpc.shutdown.drain",
+		Usage: "On shutdown, time given to in-flight RPC requests to complete before connections (and websocket subscriptions) are closed",
+		Value: 5 * time.Second,
+	}
 	CaplinBackfillingFlag = cli.BoolFlag{
 		Name:  "caplin.backfilling",
 		Usage: "sets whether backfilling is enabled for caplin",
@@ -1383,6 +1403,11 @@ func setDataDir(ctx *cli.Context, cfg *nodecfg.Config) {
 	if szLimit%256 != 0 || szLimit < 256 {
 		panic(fmt.Errorf("invalid --db.size.limit: %s=%d, see: %s", ctx.String(DbSizeLimitFlag.Name), szLimit, DbSizeLimitFlag.Usage))
 	}
+	if maxSize := ctx.String(DatadirMaxSizeFlag.Name); maxSize != "" {
+		if err := cfg.DatadirMaxSize.UnmarshalText([]byte(maxSize)); err != nil {
+			panic(fmt.Errorf("invalid --%s: %s, %w", DatadirMaxSizeFlag.Name, maxSize, err))
+		}
+	}
 }
 
 func setDataDirCobra(f *pflag.FlagSet, cfg *nodecfg.Config) {
@@ -1489,6 +1514,9 @@ func setTxPool(ctx *cli.Context, fullCfg *ethconfig.Config) {
 	if ctx.IsSet(TxPoolBlobPriceBumpFlag.Name) {
 		fullCfg.TxPool.BlobPriceBump = ctx.Uint64(TxPoolBlobPriceBumpFlag.Name)
 	}
+	if ctx.IsSet(TxPoolAccountAbstractionFlag.Name) {
+		fullCfg.TxPool.AccountAbstraction = ctx.Bool(TxPoolAccountAbstractionFlag.Name)
+	}
 	cfg.CommitEvery = common2.RandomizeDuration(ctx.Duration(TxPoolCommitEveryFlag.Name))
 }
 
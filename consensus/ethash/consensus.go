@@ -273,6 +273,9 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, pa
 	}
 	// Verify the engine specific seal securing the block
 	if seal {
+		if err := types.VerifyHeaderSeal(chain.Config(), header); err != nil {
+			return err
+		}
 		if err := ethash.VerifySeal(nil, header); err != nil {
 			return err
 		}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
+
+	cmath "github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// RIP-7560 (experimental) native account abstraction.
+//
+// Transaction is processed in frames: deployment (optional), account validation, paymaster
+// validation (optional), execution and paymaster post-op (optional). Failure of any validation frame
+// makes the transaction invalid - it can't be included into a block. Failure of execution or post-op
+// frames is recorded in the receipt as usual.
+var (
+	// AAEntryPointAddress - caller of validation, execution and post-op frames
+	AAEntryPointAddress = libcommon.HexToAddress("0x0000000000000000000000000000000000007560")
+	// AASenderCreatorAddress - caller of the deployment frame
+	AASenderCreatorAddress = libcommon.HexToAddress("0x00000000000000000000000000000000ffff7560")
+)
+
+// AAVersion - version passed to the validation frames
+const AAVersion = 0
+
+// aaMaxContextSize - max size of the context returned by the paymaster validation frame
+const aaMaxContextSize = 65536
+
+var (
+	aaValidateTransactionSelector          = crypto.Keccak256([]byte("validateTransaction(uint256,bytes32,bytes)"))[:4]
+	aaValidatePaymasterTransactionSelector = crypto.Keccak256([]byte("validatePaymasterTransaction(uint256,bytes32,bytes)"))[:4]
+	aaPostPaymasterTransactionSelector     = crypto.Keccak256([]byte("postPaymasterTransaction(bool,uint256,bytes)"))[:4]
+)
+
+// AAValidationResult - outcome of the validation frames, needed by the execution
+type AAValidationResult struct {
+	GasPrice         *uint256.Int
+	GasUsed          uint64 // AABaseGas, calldata cost and gas used by deployment and validation frames
+	PaymasterContext []byte
+}
+
+// ValidateAATransaction checks the nonce, charges the gas payer and runs deployment and validation frames.
+// On error state may be partially modified - the caller is responsible for reverting it.
+func ValidateAATransaction(evm *vm.EVM, tx *types.AccountAbstractionTransaction, gp *GasPool) (*AAValidationResult, error) {
+	ibs := evm.IntraBlockState()
+	rules := evm.ChainRules()
+	if !rules.IsRip7560 {
+		return nil, fmt.Errorf("%w: account abstraction transactions are not activated", ErrTxTypeNotSupported)
+	}
+	sender := tx.SenderAddress
+
+	if stNonce := ibs.GetNonce(sender); stNonce < tx.Nonce {
+		return nil, fmt.Errorf("%w: address %v, tx: %d state: %d", ErrNonceTooHigh, sender.Hex(), tx.Nonce, stNonce)
+	} else if stNonce > tx.Nonce {
+		return nil, fmt.Errorf("%w: address %v, tx: %d state: %d", ErrNonceTooLow, sender.Hex(), tx.Nonce, stNonce)
+	} else if stNonce+1 < stNonce {
+		return nil, fmt.Errorf("%w: address %v, nonce: %d", ErrNonceMax, sender.Hex(), stNonce)
+	}
+
+	gasPrice := tx.GetFeeCap().Clone()
+	if rules.IsLondon {
+		if err := CheckEip1559TxGasFeeCap(sender, tx.GetFeeCap(), tx.GetTip(), evm.Context.BaseFee, false); err != nil {
+			return nil, err
+		}
+		gasPrice = cmath.Min256(new(uint256.Int).Add(evm.Context.BaseFee, tx.GetTip()), tx.GetFeeCap())
+	}
+
+	// Buy gas: the payer is charged for all the frames upfront, unused gas is refunded after execution
+	totalGas := tx.TotalGasLimit()
+	cost, overflow := new(uint256.Int).MulOverflow(gasPrice, uint256.NewInt(totalGas))
+	if overflow {
+		return nil, fmt.Errorf("%w: address %v", ErrInsufficientFunds, sender.Hex())
+	}
+	if cost, overflow = cost.AddOverflow(cost, tx.BuilderFee); overflow {
+		return nil, fmt.Errorf("%w: address %v", ErrInsufficientFunds, sender.Hex())
+	}
+	payer := tx.GasPayer()
+	if have := ibs.GetBalance(payer); have.Lt(cost) {
+		return nil, fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, payer.Hex(), have, cost)
+	}
+	if err := gp.SubGas(totalGas); err != nil {
+		return nil, err
+	}
+	ibs.SubBalance(payer, cost)
+
+	ibs.Prepare(rules, sender, evm.Context.Coinbase, &sender, vm.ActivePrecompiles(rules), tx.GetAccessList())
+	ibs.AddAddressToAccessList(AAEntryPointAddress)
+	if tx.Paymaster != nil {
+		ibs.AddAddressToAccessList(*tx.Paymaster)
+	}
+	if tx.Deployer != nil {
+		ibs.AddAddressToAccessList(*tx.Deployer)
+	}
+
+	// Calldata of all the frames and access list are paid from the validation gas
+	data := bytes.Join([][]byte{tx.DeployerData, tx.PaymasterData, tx.ExecutionData, tx.AuthorizationData}, nil)
+	intrinsicGas, err := IntrinsicGas(data, tx.GetAccessList(), false, rules.IsHomestead, rules.IsIstanbul, false)
+	if err != nil {
+		return nil, err
+	}
+	intrinsicGas -= params.TxGas // replaced by AABaseGas
+	if tx.ValidationGasLimit < intrinsicGas {
+		return nil, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, tx.ValidationGasLimit, intrinsicGas)
+	}
+	validationGas := tx.ValidationGasLimit - intrinsicGas
+
+	if tx.Deployer != nil {
+		if ibs.GetCodeSize(sender) != 0 {
+			return nil, fmt.Errorf("%w: sender %v already deployed", ErrAAValidationFailed, sender.Hex())
+		}
+		_, gasLeft, err := evm.Call(vm.AccountRef(AASenderCreatorAddress), *tx.Deployer, tx.DeployerData, validationGas, uint256.NewInt(0), false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: deployment frame: %v", ErrAAValidationFailed, err)
+		}
+		validationGas = gasLeft
+	}
+	if ibs.GetCodeSize(sender) == 0 {
+		return nil, fmt.Errorf("%w: sender %v has no code", ErrAAValidationFailed, sender.Hex())
+	}
+
+	txHash := tx.SigningHash(evm.ChainConfig().ChainID)
+	var txRlp bytes.Buffer
+	if err := tx.MarshalBinary(&txRlp); err != nil {
+		return nil, err
+	}
+
+	ret, gasLeft, err := evm.Call(vm.AccountRef(AAEntryPointAddress), sender, aaCallData(aaValidateTransactionSelector, txHash, txRlp.Bytes()), validationGas, uint256.NewInt(0), false)
+	if err != nil {
+		return nil, fmt.Errorf("%w: account validation frame: %v", ErrAAValidationFailed, err)
+	}
+	if err := checkAAValidationData(ret, aaValidateTransactionSelector, evm.Context.Time); err != nil {
+		return nil, fmt.Errorf("%w: account validation frame: %v", ErrAAValidationFailed, err)
+	}
+	result := &AAValidationResult{
+		GasPrice: gasPrice,
+		GasUsed:  fixedgas.AABaseGas + tx.ValidationGasLimit - gasLeft,
+	}
+
+	if tx.Paymaster != nil {
+		ret, gasLeft, err := evm.Call(vm.AccountRef(AAEntryPointAddress), *tx.Paymaster, aaCallData(aaValidatePaymasterTransactionSelector, txHash, txRlp.Bytes()), tx.PaymasterValidationGasLimit, uint256.NewInt(0), false)
+		if err != nil {
+			return nil, fmt.Errorf("%w: paymaster validation frame: %v", ErrAAValidationFailed, err)
+		}
+		if result.PaymasterContext, err = decodePaymasterValidation(ret, evm.Context.Time); err != nil {
+			return nil, fmt.Errorf("%w: paymaster validation frame: %v", ErrAAValidationFailed, err)
+		}
+		result.GasUsed += tx.PaymasterValidationGasLimit - gasLeft
+	}
+	return result, nil
+}
+
+// ExecuteAATransaction runs execution and post-op frames of the validated transaction, refunds unused gas
+// and pays the block producer.
+func ExecuteAATransaction(evm *vm.EVM, tx *types.AccountAbstractionTransaction, validation *AAValidationResult, gp *GasPool, refunds bool) (*ExecutionResult, error) {
+	ibs := evm.IntraBlockState()
+	rules := evm.ChainRules()
+	sender := tx.SenderAddress
+
+	ibs.SetNonce(sender, ibs.GetNonce(sender)+1)
+
+	snapshot := ibs.Snapshot()
+	ret, gasLeft, vmerr := evm.Call(vm.AccountRef(AAEntryPointAddress), sender, tx.ExecutionData, tx.Gas, uint256.NewInt(0), false)
+	gasUsed := validation.GasUsed + tx.Gas - gasLeft
+
+	if len(validation.PaymasterContext) > 0 {
+		actualGasCost := new(uint256.Int).Mul(uint256.NewInt(gasUsed), validation.GasPrice)
+		_, postOpGasLeft, err := evm.Call(vm.AccountRef(AAEntryPointAddress), *tx.Paymaster, aaPostOpCallData(vmerr == nil, actualGasCost, validation.PaymasterContext), tx.PostOpGasLimit, uint256.NewInt(0), false)
+		gasUsed += tx.PostOpGasLimit - postOpGasLeft
+		if err != nil {
+			// paymaster refused to pay for the execution - execution frame is reverted, but gas is still charged
+			ibs.RevertToSnapshot(snapshot)
+			vmerr = fmt.Errorf("paymaster post-op frame: %w", err)
+		}
+	}
+
+	if refunds {
+		refundQuotient := params.RefundQuotient
+		if rules.IsLondon {
+			refundQuotient = params.RefundQuotientEIP3529
+		}
+		gasUsed -= min(gasUsed/refundQuotient, ibs.GetRefund())
+	}
+	gasRemaining := tx.TotalGasLimit() - gasUsed
+	ibs.AddBalance(tx.GasPayer(), new(uint256.Int).Mul(uint256.NewInt(gasRemaining), validation.GasPrice))
+	gp.AddGas(gasRemaining)
+
+	effectiveTip := validation.GasPrice
+	if rules.IsLondon {
+		effectiveTip = new(uint256.Int).Sub(validation.GasPrice, evm.Context.BaseFee)
+	}
+	amount := new(uint256.Int).Mul(uint256.NewInt(gasUsed), effectiveTip)
+	amount.Add(amount, tx.BuilderFee)
+	ibs.AddBalance(evm.Context.Coinbase, amount)
+	if rules.IsLondon {
+		if burntContractAddress := evm.ChainConfig().GetBurntContract(evm.Context.BlockNumber, evm.Context.Time); burntContractAddress != nil {
+			ibs.AddBalance(*burntContractAddress, new(uint256.Int).Mul(uint256.NewInt(gasUsed), evm.Context.BaseFee))
+		}
+	}
+
+	return &ExecutionResult{
+		UsedGas:    gasUsed,
+		Err:        vmerr,
+		ReturnData: ret,
+	}, nil
+}
+
+// ApplyAATransaction runs all the frames of the transaction. As ApplyMessage, returns error only if
+// the transaction is invalid; state changes made by validation frames are reverted in this case.
+func ApplyAATransaction(evm *vm.EVM, tx *types.AccountAbstractionTransaction, gp *GasPool, refunds bool) (*ExecutionResult, error) {
+	ibs := evm.IntraBlockState()
+	if evm.Config().Debug {
+		evm.Config().Tracer.CaptureTxStart(tx.TotalGasLimit())
+	}
+	snapshot := ibs.Snapshot()
+	gasBefore := gp.Gas()
+	validation, err := ValidateAATransaction(evm, tx, gp)
+	if err != nil {
+		ibs.RevertToSnapshot(snapshot)
+		gp.AddGas(gasBefore - gp.Gas())
+		if evm.Config().Debug {
+			evm.Config().Tracer.CaptureTxEnd(tx.TotalGasLimit())
+		}
+		return nil, err
+	}
+	result, err := ExecuteAATransaction(evm, tx, validation, gp, refunds)
+	if evm.Config().Debug {
+		evm.Config().Tracer.CaptureTxEnd(tx.TotalGasLimit() - result.UsedGas)
+	}
+	return result, err
+}
+
+// aaCallData - ABI encoding of (uint256 version, bytes32 txHash, bytes transaction)
+func aaCallData(selector []byte, txHash libcommon.Hash, txRlp []byte) []byte {
+	data := make([]byte, 0, 4+32*4+len(txRlp)+31)
+	data = append(data, selector...)
+	data = append(data, uint256.NewInt(AAVersion).PaddedBytes(32)...)
+	data = append(data, txHash[:]...)
+	return appendABIBytes(data, 3*32, txRlp)
+}
+
+// aaPostOpCallData - ABI encoding of (bool success, uint256 actualGasCost, bytes context)
+func aaPostOpCallData(success bool, actualGasCost *uint256.Int, context []byte) []byte {
+	data := make([]byte, 0, 4+32*4+len(context)+31)
+	data = append(data, aaPostPaymasterTransactionSelector...)
+	var flag [32]byte
+	if success {
+		flag[31] = 1
+	}
+	data = append(data, flag[:]...)
+	data = append(data, actualGasCost.PaddedBytes(32)...)
+	return appendABIBytes(data, 3*32, context)
+}
+
+// appendABIBytes appends offset of the dynamic `bytes` argument (the last one), its length and padded content
+func appendABIBytes(data []byte, offset uint64, b []byte) []byte {
+	data = append(data, uint256.NewInt(offset).PaddedBytes(32)...)
+	data = append(data, uint256.NewInt(uint64(len(b))).PaddedBytes(32)...)
+	data = append(data, b...)
+	if rem := len(b) % 32; rem != 0 {
+		data = append(data, make([]byte, 32-rem)...)
+	}
+	return data
+}
+
+// checkAAValidationData - validation data is bytes32: magic (selector of the frame's method, 4 bytes),
+// validUntil (6 bytes, 0 - no expiration) and validAfter (6 bytes), the rest is zero
+func checkAAValidationData(ret []byte, magic []byte, blockTime uint64) error {
+	if len(ret) < 32 {
+		return fmt.Errorf("unexpected return size: %d", len(ret))
+	}
+	if !bytes.Equal(ret[:4], magic) {
+		return fmt.Errorf("transaction is not authorized")
+	}
+	var buf [8]byte
+	copy(buf[2:], ret[4:10])
+	validUntil := binary.BigEndian.Uint64(buf[:])
+	copy(buf[2:], ret[10:16])
+	validAfter := binary.BigEndian.Uint64(buf[:])
+	if validUntil != 0 && blockTime > validUntil {
+		return fmt.Errorf("transaction expired at %d", validUntil)
+	}
+	if blockTime < validAfter {
+		return fmt.Errorf("transaction is not valid until %d", validAfter)
+	}
+	return nil
+}
+
+// decodePaymasterValidation - paymaster returns ABI encoded (bytes32 validationData, bytes context)
+func decodePaymasterValidation(ret []byte, blockTime uint64) ([]byte, error) {
+	if len(ret) < 3*32 {
+		return nil, fmt.Errorf("unexpected return size: %d", len(ret))
+	}
+	if err := checkAAValidationData(ret[:32], aaValidatePaymasterTransactionSelector, blockTime); err != nil {
+		return nil, err
+	}
+	offset := new(uint256.Int).SetBytes(ret[32:64])
+	if !offset.IsUint64() || offset.Uint64() > uint64(len(ret)-32) {
+		return nil, fmt.Errorf("invalid context offset")
+	}
+	start := offset.Uint64() + 32
+	size := new(uint256.Int).SetBytes(ret[start-32 : start])
+	if !size.IsUint64() || size.Uint64() > aaMaxContextSize || start+size.Uint64() > uint64(len(ret)) {
+		return nil, fmt.Errorf("invalid context size")
+	}
+	return libcommon.CopyBytes(ret[start : start+size.Uint64()]), nil
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// returnWordCode - contract which returns given 32 bytes for any call
+func returnWordCode(word [32]byte) []byte {
+	code := append([]byte{byte(vm.PUSH32)}, word[:]...)
+	return append(code, byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN))
+}
+
+func TestApplyAATransaction(t *testing.T) {
+	db := memdb.NewTestDB(t)
+
+	config := *params.TestChainConfig
+	config.LondonBlock = big.NewInt(0)
+	config.Rip7560Time = big.NewInt(0)
+
+	sender := libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	coinbase := libcommon.HexToAddress("0x3000000000000000000000000000000000000003")
+	var acceptAll, rejectAll [32]byte
+	copy(acceptAll[:], aaValidateTransactionSelector)
+
+	header := &types.Header{Number: big.NewInt(1), Time: 1, GasLimit: 30_000_000, BaseFee: big.NewInt(7), Difficulty: big.NewInt(0)}
+	newEVM := func(t *testing.T, code []byte) (*vm.EVM, *state.IntraBlockState) {
+		tx, err := db.BeginRo(context.Background())
+		require.NoError(t, err)
+		t.Cleanup(tx.Rollback)
+		ibs := state.New(state.NewPlainStateReader(tx))
+		ibs.SetCode(sender, code)
+		ibs.AddBalance(sender, uint256.NewInt(1e18))
+		blockContext := NewEVMBlockContext(header, func(n uint64) libcommon.Hash { return libcommon.Hash{} }, nil, &coinbase)
+		return vm.NewEVM(blockContext, evmtypes.TxContext{}, ibs, &config, vm.Config{}), ibs
+	}
+	txn := &types.AccountAbstractionTransaction{
+		ChainID:            uint256.NewInt(config.ChainID.Uint64()),
+		SenderAddress:      sender,
+		ExecutionData:      []byte{0x01},
+		BuilderFee:         uint256.NewInt(5),
+		Tip:                uint256.NewInt(2),
+		FeeCap:             uint256.NewInt(10),
+		ValidationGasLimit: 100_000,
+		Gas:                100_000,
+	}
+
+	t.Run("authorized", func(t *testing.T) {
+		evm, ibs := newEVM(t, returnWordCode(acceptAll))
+		gp := new(GasPool).AddGas(header.GasLimit)
+		result, err := ApplyAATransaction(evm, txn, gp, true)
+		require.NoError(t, err)
+		require.False(t, result.Failed())
+		require.Greater(t, result.UsedGas, uint64(0))
+		require.Equal(t, header.GasLimit-result.UsedGas, gp.Gas())
+		require.Equal(t, uint64(1), ibs.GetNonce(sender))
+
+		// effective gas price is baseFee + tip = 9
+		cost := new(uint256.Int).Add(uint256.NewInt(result.UsedGas*9), txn.BuilderFee)
+		require.Equal(t, new(uint256.Int).Sub(uint256.NewInt(1e18), cost), ibs.GetBalance(sender))
+		require.Equal(t, uint256.NewInt(result.UsedGas*2+5), ibs.GetBalance(coinbase))
+	})
+
+	t.Run("not authorized", func(t *testing.T) {
+		evm, ibs := newEVM(t, returnWordCode(rejectAll))
+		gp := new(GasPool).AddGas(header.GasLimit)
+		_, err := ApplyAATransaction(evm, txn, gp, true)
+		require.ErrorIs(t, err, ErrAAValidationFailed)
+		require.Equal(t, header.GasLimit, gp.Gas())
+		require.Equal(t, uint64(0), ibs.GetNonce(sender))
+		require.Equal(t, uint256.NewInt(1e18), ibs.GetBalance(sender))
+	})
+
+	t.Run("not activated", func(t *testing.T) {
+		evm, _ := newEVM(t, returnWordCode(acceptAll))
+		noAA := config
+		noAA.Rip7560Time = nil
+		evm = vm.NewEVM(evm.Context, evmtypes.TxContext{}, evm.IntraBlockState(), &noAA, vm.Config{})
+		_, err := ApplyAATransaction(evm, txn, new(GasPool).AddGas(header.GasLimit), true)
+		require.ErrorIs(t, err, ErrTxTypeNotSupported)
+	})
+}
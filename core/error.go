@@ -107,4 +107,8 @@ var (
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	// See EIP-3607: Reject transactions from senders with deployed code.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
+
+	// ErrAAValidationFailed is returned if a validation frame of the account abstraction transaction
+	// failed or didn't authorize the transaction. See RIP-7560.
+	ErrAAValidationFailed = errors.New("account abstraction validation failed")
 )
@@ -251,6 +251,12 @@ func GatherForks(config *chain.Config, genesisTime uint64) (heightForks []uint64
 		if config.Bor.GetNapoliBlock() != nil {
 			heightForks = append(heightForks, config.Bor.GetNapoliBlock().Uint64())
 		}
+		heightForks = append(heightForks, config.Bor.GetForkBlocks()...)
+		for _, t := range config.Bor.GetForkTimes() {
+			if t > genesisTime {
+				timeForks = append(timeForks, t)
+			}
+		}
 	}
 
 	// Sort the fork block numbers & times to permit chronological XOR
@@ -58,7 +58,12 @@ func applyTransaction(config *chain.Config, engine consensus.EngineReader, gp *G
 	// Update the evm with the new transaction context.
 	evm.Reset(txContext, ibs)
 
-	result, err := ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+	var result *ExecutionResult
+	if aaTx, ok := tx.(*types.AccountAbstractionTransaction); ok {
+		result, err = ApplyAATransaction(evm, aaTx, gp, true /* refunds */)
+	} else {
+		result, err = ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,467 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/fixedgas"
+	rlp2 "github.com/ledgerwatch/erigon-lib/rlp"
+	types2 "github.com/ledgerwatch/erigon-lib/types"
+
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+var ErrAATxNotSigned = errors.New("account abstraction transactions are authorized by the sender contract, not signed")
+
+// AccountAbstractionTransaction is the experimental RIP-7560 native account abstraction transaction.
+// It has no ECDSA signature: SenderAddress is a smart contract account which authorizes the transaction
+// in its validation frame (see core.ApplyAATransaction). Legacy nonce only, RIP-7712 nonce keys are not supported.
+type AccountAbstractionTransaction struct {
+	TransactionMisc
+	ChainID       *uint256.Int
+	Nonce         uint64
+	SenderAddress libcommon.Address
+	Deployer      *libcommon.Address // factory which deploys the sender account if it has no code yet
+	DeployerData  []byte
+	Paymaster     *libcommon.Address // pays for gas instead of the sender, if set
+	PaymasterData []byte
+	ExecutionData []byte // calldata of the execution frame
+	BuilderFee    *uint256.Int
+	Tip           *uint256.Int // maxPriorityFeePerGas
+	FeeCap        *uint256.Int // maxFeePerGas
+	// ValidationGasLimit covers calldata cost, deployment and the account validation frame
+	ValidationGasLimit          uint64
+	PaymasterValidationGasLimit uint64
+	PostOpGasLimit              uint64
+	Gas                         uint64 // callGasLimit - gas limit of the execution frame
+	AccessList                  types2.AccessList
+	AuthorizationData           []byte // passed to the account validation frame, i.e. signature of the account owner
+}
+
+// TotalGasLimit is the gas bought for the transaction: sum of the limits of all frames and AABaseGas
+func (tx *AccountAbstractionTransaction) TotalGasLimit() uint64 {
+	return fixedgas.AABaseGas + tx.ValidationGasLimit + tx.PaymasterValidationGasLimit + tx.Gas + tx.PostOpGasLimit
+}
+
+// GasPayer returns the account charged for gas
+func (tx *AccountAbstractionTransaction) GasPayer() libcommon.Address {
+	if tx.Paymaster != nil {
+		return *tx.Paymaster
+	}
+	return tx.SenderAddress
+}
+
+func (tx *AccountAbstractionTransaction) Type() byte               { return AccountAbstractionTxType }
+func (tx *AccountAbstractionTransaction) GetChainID() *uint256.Int { return tx.ChainID }
+func (tx *AccountAbstractionTransaction) GetNonce() uint64         { return tx.Nonce }
+func (tx *AccountAbstractionTransaction) GetPrice() *uint256.Int   { return tx.Tip }
+func (tx *AccountAbstractionTransaction) GetTip() *uint256.Int     { return tx.Tip }
+func (tx *AccountAbstractionTransaction) GetFeeCap() *uint256.Int  { return tx.FeeCap }
+func (tx *AccountAbstractionTransaction) GetGas() uint64           { return tx.TotalGasLimit() }
+func (tx *AccountAbstractionTransaction) GetBlobGas() uint64       { return 0 }
+func (tx *AccountAbstractionTransaction) GetValue() *uint256.Int   { return uint256.NewInt(0) }
+func (tx *AccountAbstractionTransaction) GetData() []byte          { return tx.ExecutionData }
+func (tx *AccountAbstractionTransaction) Protected() bool          { return true }
+func (tx *AccountAbstractionTransaction) IsContractDeploy() bool   { return false }
+func (tx *AccountAbstractionTransaction) Unwrap() Transaction      { return tx }
+func (tx *AccountAbstractionTransaction) GetBlobHashes() []libcommon.Hash {
+	return []libcommon.Hash{}
+}
+func (tx *AccountAbstractionTransaction) GetAccessList() types2.AccessList {
+	return tx.AccessList
+}
+
+func (tx *AccountAbstractionTransaction) GetEffectiveGasTip(baseFee *uint256.Int) *uint256.Int {
+	if baseFee == nil {
+		return tx.GetTip()
+	}
+	gasFeeCap := tx.GetFeeCap()
+	// return 0 because effectiveFee cant be < 0
+	if gasFeeCap.Lt(baseFee) {
+		return uint256.NewInt(0)
+	}
+	effectiveFee := new(uint256.Int).Sub(gasFeeCap, baseFee)
+	if tx.GetTip().Lt(effectiveFee) {
+		return tx.GetTip()
+	}
+	return effectiveFee
+}
+
+// GetTo returns the sender account: execution frame calls it with ExecutionData
+func (tx *AccountAbstractionTransaction) GetTo() *libcommon.Address {
+	to := tx.SenderAddress
+	return &to
+}
+
+func (tx *AccountAbstractionTransaction) RawSignatureValues() (*uint256.Int, *uint256.Int, *uint256.Int) {
+	return new(uint256.Int), new(uint256.Int), new(uint256.Int)
+}
+
+func (tx *AccountAbstractionTransaction) WithSignature(signer Signer, sig []byte) (Transaction, error) {
+	return nil, ErrAATxNotSigned
+}
+
+func (tx *AccountAbstractionTransaction) FakeSign(address libcommon.Address) (Transaction, error) {
+	cpy := tx.copy()
+	cpy.SenderAddress = address
+	return cpy, nil
+}
+
+// Sender - sender is a part of the transaction, signer only checks that the transaction type is supported
+func (tx *AccountAbstractionTransaction) Sender(signer Signer) (libcommon.Address, error) {
+	return signer.Sender(tx)
+}
+
+func (tx *AccountAbstractionTransaction) cashedSender() (libcommon.Address, bool) {
+	return tx.SenderAddress, true
+}
+
+func (tx *AccountAbstractionTransaction) GetSender() (libcommon.Address, bool) {
+	return tx.SenderAddress, true
+}
+
+// SetSender is no-op: sender can't differ from SenderAddress
+func (tx *AccountAbstractionTransaction) SetSender(libcommon.Address) {}
+
+func (tx *AccountAbstractionTransaction) copy() *AccountAbstractionTransaction {
+	cpy := &AccountAbstractionTransaction{
+		ChainID:                     new(uint256.Int),
+		Nonce:                       tx.Nonce,
+		SenderAddress:               tx.SenderAddress,
+		DeployerData:                libcommon.CopyBytes(tx.DeployerData),
+		PaymasterData:               libcommon.CopyBytes(tx.PaymasterData),
+		ExecutionData:               libcommon.CopyBytes(tx.ExecutionData),
+		BuilderFee:                  new(uint256.Int),
+		Tip:                         new(uint256.Int),
+		FeeCap:                      new(uint256.Int),
+		ValidationGasLimit:          tx.ValidationGasLimit,
+		PaymasterValidationGasLimit: tx.PaymasterValidationGasLimit,
+		PostOpGasLimit:              tx.PostOpGasLimit,
+		Gas:                         tx.Gas,
+		AccessList:                  make(types2.AccessList, len(tx.AccessList)),
+		AuthorizationData:           libcommon.CopyBytes(tx.AuthorizationData),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	if tx.Deployer != nil {
+		deployer := *tx.Deployer
+		cpy.Deployer = &deployer
+	}
+	if tx.Paymaster != nil {
+		paymaster := *tx.Paymaster
+		cpy.Paymaster = &paymaster
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.BuilderFee != nil {
+		cpy.BuilderFee.Set(tx.BuilderFee)
+	}
+	if tx.Tip != nil {
+		cpy.Tip.Set(tx.Tip)
+	}
+	if tx.FeeCap != nil {
+		cpy.FeeCap.Set(tx.FeeCap)
+	}
+	return cpy
+}
+
+func (tx *AccountAbstractionTransaction) EncodingSize() int {
+	payloadSize, _ := tx.payloadSize()
+	// Add envelope size and type size
+	return 1 + rlp2.ListPrefixLen(payloadSize) + payloadSize
+}
+
+func optionalAddressSize(addr *libcommon.Address) int {
+	if addr == nil {
+		return 1
+	}
+	return 21
+}
+
+func (tx *AccountAbstractionTransaction) payloadSize() (payloadSize int, accessListLen int) {
+	payloadSize += 1 + rlp.Uint256LenExcludingHead(tx.ChainID)
+	payloadSize += 1 + rlp.IntLenExcludingHead(tx.Nonce)
+	payloadSize += 21 // SenderAddress
+	payloadSize += optionalAddressSize(tx.Deployer)
+	payloadSize += rlp2.StringLen(tx.DeployerData)
+	payloadSize += optionalAddressSize(tx.Paymaster)
+	payloadSize += rlp2.StringLen(tx.PaymasterData)
+	payloadSize += rlp2.StringLen(tx.ExecutionData)
+	payloadSize += 1 + rlp.Uint256LenExcludingHead(tx.BuilderFee)
+	payloadSize += 1 + rlp.Uint256LenExcludingHead(tx.Tip)
+	payloadSize += 1 + rlp.Uint256LenExcludingHead(tx.FeeCap)
+	payloadSize += 1 + rlp.IntLenExcludingHead(tx.ValidationGasLimit)
+	payloadSize += 1 + rlp.IntLenExcludingHead(tx.PaymasterValidationGasLimit)
+	payloadSize += 1 + rlp.IntLenExcludingHead(tx.PostOpGasLimit)
+	payloadSize += 1 + rlp.IntLenExcludingHead(tx.Gas)
+	accessListLen = accessListSize(tx.AccessList)
+	payloadSize += rlp2.ListPrefixLen(accessListLen) + accessListLen
+	payloadSize += rlp2.StringLen(tx.AuthorizationData)
+	return payloadSize, accessListLen
+}
+
+func encodeOptionalAddress(addr *libcommon.Address, w io.Writer, b []byte) error {
+	if addr == nil {
+		b[0] = 128
+		_, err := w.Write(b[:1])
+		return err
+	}
+	b[0] = 128 + 20
+	if _, err := w.Write(b[:1]); err != nil {
+		return err
+	}
+	_, err := w.Write(addr[:])
+	return err
+}
+
+func decodeOptionalAddress(s *rlp.Stream) (*libcommon.Address, error) {
+	b, err := s.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if len(b) != 20 {
+		return nil, fmt.Errorf("wrong size for address: %d", len(b))
+	}
+	addr := libcommon.BytesToAddress(b)
+	return &addr, nil
+}
+
+func (tx *AccountAbstractionTransaction) encodePayload(w io.Writer, b []byte, payloadSize, accessListLen int) error {
+	if err := EncodeStructSizePrefix(payloadSize, w, b); err != nil {
+		return err
+	}
+	if err := tx.ChainID.EncodeRLP(w); err != nil {
+		return err
+	}
+	if err := rlp.EncodeInt(tx.Nonce, w, b); err != nil {
+		return err
+	}
+	if err := encodeOptionalAddress(&tx.SenderAddress, w, b); err != nil {
+		return err
+	}
+	if err := encodeOptionalAddress(tx.Deployer, w, b); err != nil {
+		return err
+	}
+	if err := rlp.EncodeString(tx.DeployerData, w, b); err != nil {
+		return err
+	}
+	if err := encodeOptionalAddress(tx.Paymaster, w, b); err != nil {
+		return err
+	}
+	if err := rlp.EncodeString(tx.PaymasterData, w, b); err != nil {
+		return err
+	}
+	if err := rlp.EncodeString(tx.ExecutionData, w, b); err != nil {
+		return err
+	}
+	if err := tx.BuilderFee.EncodeRLP(w); err != nil {
+		return err
+	}
+	if err := tx.Tip.EncodeRLP(w); err != nil {
+		return err
+	}
+	if err := tx.FeeCap.EncodeRLP(w); err != nil {
+		return err
+	}
+	for _, gas := range []uint64{tx.ValidationGasLimit, tx.PaymasterValidationGasLimit, tx.PostOpGasLimit, tx.Gas} {
+		if err := rlp.EncodeInt(gas, w, b); err != nil {
+			return err
+		}
+	}
+	if err := EncodeStructSizePrefix(accessListLen, w, b); err != nil {
+		return err
+	}
+	if err := encodeAccessList(tx.AccessList, w, b); err != nil {
+		return err
+	}
+	return rlp.EncodeString(tx.AuthorizationData, w, b)
+}
+
+// MarshalBinary returns the canonical encoding of the transaction: type and payload
+func (tx *AccountAbstractionTransaction) MarshalBinary(w io.Writer) error {
+	payloadSize, accessListLen := tx.payloadSize()
+	var b [33]byte
+	b[0] = AccountAbstractionTxType
+	if _, err := w.Write(b[:1]); err != nil {
+		return err
+	}
+	return tx.encodePayload(w, b[:], payloadSize, accessListLen)
+}
+
+func (tx *AccountAbstractionTransaction) EncodeRLP(w io.Writer) error {
+	payloadSize, accessListLen := tx.payloadSize()
+	// size of struct prefix and TxType
+	envelopeSize := 1 + rlp2.ListPrefixLen(payloadSize) + payloadSize
+	var b [33]byte
+	if err := rlp.EncodeStringSizePrefix(envelopeSize, w, b[:]); err != nil {
+		return err
+	}
+	b[0] = AccountAbstractionTxType
+	if _, err := w.Write(b[:1]); err != nil {
+		return err
+	}
+	return tx.encodePayload(w, b[:], payloadSize, accessListLen)
+}
+
+func (tx *AccountAbstractionTransaction) DecodeRLP(s *rlp.Stream) error {
+	_, err := s.List()
+	if err != nil {
+		return err
+	}
+	var b []byte
+	if b, err = s.Uint256Bytes(); err != nil {
+		return err
+	}
+	tx.ChainID = new(uint256.Int).SetBytes(b)
+	if tx.Nonce, err = s.Uint(); err != nil {
+		return err
+	}
+	sender, err := decodeOptionalAddress(s)
+	if err != nil {
+		return fmt.Errorf("read SenderAddress: %w", err)
+	}
+	if sender == nil {
+		return fmt.Errorf("missing SenderAddress")
+	}
+	tx.SenderAddress = *sender
+	if tx.Deployer, err = decodeOptionalAddress(s); err != nil {
+		return fmt.Errorf("read Deployer: %w", err)
+	}
+	if tx.DeployerData, err = s.Bytes(); err != nil {
+		return err
+	}
+	if tx.Paymaster, err = decodeOptionalAddress(s); err != nil {
+		return fmt.Errorf("read Paymaster: %w", err)
+	}
+	if tx.PaymasterData, err = s.Bytes(); err != nil {
+		return err
+	}
+	if tx.ExecutionData, err = s.Bytes(); err != nil {
+		return err
+	}
+	if b, err = s.Uint256Bytes(); err != nil {
+		return err
+	}
+	tx.BuilderFee = new(uint256.Int).SetBytes(b)
+	if b, err = s.Uint256Bytes(); err != nil {
+		return err
+	}
+	tx.Tip = new(uint256.Int).SetBytes(b)
+	if b, err = s.Uint256Bytes(); err != nil {
+		return err
+	}
+	tx.FeeCap = new(uint256.Int).SetBytes(b)
+	if tx.ValidationGasLimit, err = s.Uint(); err != nil {
+		return err
+	}
+	if tx.PaymasterValidationGasLimit, err = s.Uint(); err != nil {
+		return err
+	}
+	if tx.PostOpGasLimit, err = s.Uint(); err != nil {
+		return err
+	}
+	if tx.Gas, err = s.Uint(); err != nil {
+		return err
+	}
+	tx.AccessList = types2.AccessList{}
+	if err = decodeAccessList(&tx.AccessList, s); err != nil {
+		return err
+	}
+	if tx.AuthorizationData, err = s.Bytes(); err != nil {
+		return err
+	}
+	return s.ListEnd()
+}
+
+// AsMessage returns the transaction as a core.Message. Message describes only the execution frame,
+// deployment and validation frames are run by core.ApplyAATransaction.
+func (tx *AccountAbstractionTransaction) AsMessage(s Signer, baseFee *big.Int, rules *chain.Rules) (Message, error) {
+	msg := Message{
+		nonce:      tx.Nonce,
+		gasLimit:   tx.TotalGasLimit(),
+		gasPrice:   *tx.FeeCap,
+		tip:        *tx.Tip,
+		feeCap:     *tx.FeeCap,
+		to:         tx.GetTo(),
+		data:       tx.ExecutionData,
+		accessList: tx.AccessList,
+		checkNonce: true,
+	}
+	if !rules.IsRip7560 {
+		return msg, errors.New("account abstraction transactions require RIP-7560")
+	}
+	if baseFee != nil {
+		overflow := msg.gasPrice.SetFromBig(baseFee)
+		if overflow {
+			return msg, fmt.Errorf("gasPrice higher than 2^256-1")
+		}
+	}
+	msg.gasPrice.Add(&msg.gasPrice, tx.Tip)
+	if msg.gasPrice.Gt(tx.FeeCap) {
+		msg.gasPrice.Set(tx.FeeCap)
+	}
+
+	var err error
+	msg.from, err = tx.Sender(s)
+	return msg, err
+}
+
+func (tx *AccountAbstractionTransaction) Hash() libcommon.Hash {
+	if hash := tx.hash.Load(); hash != nil {
+		return *hash.(*libcommon.Hash)
+	}
+	hash := prefixedRlpHash(AccountAbstractionTxType, []interface{}{
+		tx.ChainID,
+		tx.Nonce,
+		tx.SenderAddress,
+		tx.Deployer,
+		tx.DeployerData,
+		tx.Paymaster,
+		tx.PaymasterData,
+		tx.ExecutionData,
+		tx.BuilderFee,
+		tx.Tip,
+		tx.FeeCap,
+		tx.ValidationGasLimit,
+		tx.PaymasterValidationGasLimit,
+		tx.PostOpGasLimit,
+		tx.Gas,
+		tx.AccessList,
+		tx.AuthorizationData,
+	})
+	tx.hash.Store(&hash)
+	return hash
+}
+
+// SigningHash is the hash passed to the validation frames - it commits to everything but AuthorizationData
+func (tx *AccountAbstractionTransaction) SigningHash(chainID *big.Int) libcommon.Hash {
+	return prefixedRlpHash(
+		AccountAbstractionTxType,
+		[]interface{}{
+			chainID,
+			tx.Nonce,
+			tx.SenderAddress,
+			tx.Deployer,
+			tx.DeployerData,
+			tx.Paymaster,
+			tx.PaymasterData,
+			tx.ExecutionData,
+			tx.BuilderFee,
+			tx.Tip,
+			tx.FeeCap,
+			tx.ValidationGasLimit,
+			tx.PaymasterValidationGasLimit,
+			tx.PostOpGasLimit,
+			tx.Gas,
+			tx.AccessList,
+		})
+}
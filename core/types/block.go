@@ -92,20 +92,20 @@ type Header struct {
 	Extra       []byte            `json:"extraData"        gencodec:"required"`
 	MixDigest   libcommon.Hash    `json:"mixHash"` // prevRandao after EIP-4399
 	Nonce       BlockNonce        `json:"nonce"`
-	// AuRa extensions (alternative to MixDigest & Nonce)
+	// AuRa extensions (alternative to MixDigest & Nonce), see HeaderSealFormat
 	AuRaStep uint64
 	AuRaSeal []byte
 
-	BaseFee         *big.Int        `json:"baseFeePerGas"`   // EIP-1559
-	WithdrawalsHash *libcommon.Hash `json:"withdrawalsRoot"` // EIP-4895
+	BaseFee         *big.Int        `json:"baseFeePerGas,omitempty"`   // EIP-1559
+	WithdrawalsHash *libcommon.Hash `json:"withdrawalsRoot,omitempty"` // EIP-4895
 
 	// BlobGasUsed & ExcessBlobGas were added by EIP-4844 and are ignored in legacy headers.
-	BlobGasUsed   *uint64 `json:"blobGasUsed"`
-	ExcessBlobGas *uint64 `json:"excessBlobGas"`
+	BlobGasUsed   *uint64 `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas *uint64 `json:"excessBlobGas,omitempty"`
 
-	ParentBeaconBlockRoot *libcommon.Hash `json:"parentBeaconBlockRoot"` // EIP-4788
+	ParentBeaconBlockRoot *libcommon.Hash `json:"parentBeaconBlockRoot,omitempty"` // EIP-4788
 
-	RequestsRoot *libcommon.Hash `json:"requestsRoot"` // EIP-7685
+	RequestsRoot *libcommon.Hash `json:"requestsRoot,omitempty"` // EIP-7685
 
 	// The verkle proof is ignored in legacy headers
 	Verkle        bool
@@ -134,12 +134,7 @@ func (h *Header) EncodingSize() int {
 	// size of Extra
 	encodingSize += rlp2.StringLen(h.Extra)
 
-	if len(h.AuRaSeal) != 0 {
-		encodingSize += 1 + rlp.IntLenExcludingHead(h.AuRaStep)
-		encodingSize += rlp2.ListPrefixLen(len(h.AuRaSeal)) + len(h.AuRaSeal)
-	} else {
-		encodingSize += 33 /* MixDigest */ + 9 /* BlockNonce */
-	}
+	encodingSize += headerSealFormatOf(h).EncodingSize(h)
 
 	if h.BaseFee != nil {
 		encodingSize++
@@ -255,28 +250,8 @@ func (h *Header) EncodeRLP(w io.Writer) error {
 		return err
 	}
 
-	if len(h.AuRaSeal) > 0 {
-		if err := rlp.EncodeInt(h.AuRaStep, w, b[:]); err != nil {
-			return err
-		}
-		if err := rlp.EncodeString(h.AuRaSeal, w, b[:]); err != nil {
-			return err
-		}
-	} else {
-		b[0] = 128 + 32
-		if _, err := w.Write(b[:1]); err != nil {
-			return err
-		}
-		if _, err := w.Write(h.MixDigest.Bytes()); err != nil {
-			return err
-		}
-		b[0] = 128 + 8
-		if _, err := w.Write(b[:1]); err != nil {
-			return err
-		}
-		if _, err := w.Write(h.Nonce[:]); err != nil {
-			return err
-		}
+	if err := headerSealFormatOf(h).EncodeRLP(h, w, b[:]); err != nil {
+		return err
 	}
 
 	if h.BaseFee != nil {
@@ -416,29 +391,12 @@ func (h *Header) DecodeRLP(s *rlp.Stream) error {
 		return fmt.Errorf("read Extra: %w", err)
 	}
 
-	_, size, err := s.Kind()
+	kind, size, err := s.Kind()
 	if err != nil {
-		return fmt.Errorf("read MixDigest: %w", err)
+		return fmt.Errorf("read seal: %w", err)
 	}
-	if size != 32 { // AuRa
-		if h.AuRaStep, err = s.Uint(); err != nil {
-			return fmt.Errorf("read AuRaStep: %w", err)
-		}
-		if h.AuRaSeal, err = s.Bytes(); err != nil {
-			return fmt.Errorf("read AuRaSeal: %w", err)
-		}
-	} else {
-		if b, err = s.Bytes(); err != nil {
-			return fmt.Errorf("read MixDigest: %w", err)
-		}
-		copy(h.MixDigest[:], b)
-		if b, err = s.Bytes(); err != nil {
-			return fmt.Errorf("read Nonce: %w", err)
-		}
-		if len(b) != 8 {
-			return fmt.Errorf("wrong size for Nonce: %d", len(b))
-		}
-		copy(h.Nonce[:], b)
+	if err = detectHeaderSealFormat(kind, size).DecodeRLP(h, s); err != nil {
+		return err
 	}
 
 	// BaseFee
@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
@@ -474,6 +475,45 @@ func TestAuRaHeaderEncoding(t *testing.T) {
 	require.NoError(t, rlp.DecodeBytes(encoded, &decoded))
 
 	assert.Equal(t, header, decoded)
+
+	require.NoError(t, VerifyHeaderSeal(&chain.Config{Aura: &chain.AuRaConfig{}}, &decoded))
+	require.Error(t, VerifyHeaderSeal(&chain.Config{}, &decoded))
+	require.Error(t, VerifyHeaderSeal(&chain.Config{HeaderSeal: "unknown"}, &decoded))
+
+	decoded.AuRaSeal = nil
+	require.NoError(t, VerifyHeaderSeal(&chain.Config{}, &decoded))
+}
+
+// TestHeaderSealDecoding - header RLP of both seal formats, as produced before HeaderSealFormat, still round-trips
+func TestHeaderSealDecoding(t *testing.T) {
+	t.Parallel()
+	blockEnc := common.FromHex("f90260f901f9a083cafc574e1f51ba9dc0568fc617a08ea2429fb384059c972f13b19fa1c8dd55a01dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347948888f1f195afa192cfee860698584c030f4c9db1a0ef1552a40b7165c3cd773806b9e0c165b75356e0314bf0706f279c729f51e017a05fe50b260da6308036625b850b5d6ced6d0a9f814c0688bc91ffb7b7a3a54b67a0bc37d79753ad738a6dac4921e57392f145d8887476de3f783dfa7edae9283e52b90100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000008302000001832fefd8825208845506eb0780a0bd4472abb6659ebe3ee06ee4d7b72a00a9f4d001caca51342001075469aff49888a13a5a8c8f2bb1c4f861f85f800a82c35094095e7baea6a6c7c4c2dfeb977efac326af552d870a801ba09bea4c4daac7c7c52e093e6a4c35dbbcf8856f1af7b059ba20253e70848d094fa08a8fae537ce25ed8cb5af9adac3f141af69bd515bd2ba031522df09b97dd72b1c0")
+	ethashEnc := blockEnc[3 : 3+3+0x1f9]
+	var ethashHeader Header
+	require.NoError(t, rlp.DecodeBytes(ethashEnc, &ethashHeader))
+	assert.Equal(t, libcommon.HexToHash("bd4472abb6659ebe3ee06ee4d7b72a00a9f4d001caca51342001075469aff498"), ethashHeader.MixDigest)
+	assert.Equal(t, EncodeNonce(0xa13a5a8c8f2bb1c4), ethashHeader.Nonce)
+	assert.Empty(t, ethashHeader.AuRaSeal)
+	encoded, err := rlp.EncodeToBytes(&ethashHeader)
+	require.NoError(t, err)
+	assert.Equal(t, ethashEnc, encoded)
+
+	// Gnosis-like header (parity vanity, 65 bytes signature), encoded by the pre-HeaderSealFormat encoder
+	auraEnc := common.FromHex("f90249a08b00fcf1e541d371a3a1b79cc999a85cc3db5ee5637b5159646e1acd3613fd15a01dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d4934794571846e42308df2dad8ed792f44a8bfddf0acb4da0351780124dae86b84998c6d4fe9a88acfb41b4856b4f2c56767b51a4e2f94dd4a06a35133fbff7ea2cb5ee7635c9fb623f96d31d689d806a2bfe40a2b1d90ee99ca0324f54860e214ea896ea7a05bda30f85541be3157de77a9059a04fdb1e86baddb901000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000090fffffffffffffffffffffffffffffffe84017895f38401c9c380832ee92984635261ab9fde830203088f5061726974792d457468657265756d86312e36322e30826c698413de3abdb84175bda30f85541be059646e1acd3613fd100846e42308df2dad8ed79b9a9e91c9db994386599a683820a1394684d41fc139c4805684142e6b15a722a2e9cc51f7ee")
+	var auraHeader Header
+	require.NoError(t, rlp.DecodeBytes(auraEnc, &auraHeader))
+	assert.Equal(t, uint64(333331133), auraHeader.AuRaStep)
+	assert.Len(t, auraHeader.AuRaSeal, 65)
+	assert.Equal(t, libcommon.Hash{}, auraHeader.MixDigest)
+	encoded, err = rlp.EncodeToBytes(&auraHeader)
+	require.NoError(t, err)
+	assert.Equal(t, auraEnc, encoded)
+
+	// everything but a 32 bytes MixDigest is still decoded as AuRa seal
+	assert.Equal(t, defaultHeaderSealFormat, detectHeaderSealFormat(rlp.String, 32))
+	assert.Equal(t, legacyHeaderSealFormat, detectHeaderSealFormat(rlp.String, 8))
+	assert.Equal(t, legacyHeaderSealFormat, detectHeaderSealFormat(rlp.String, 33))
+	assert.Equal(t, legacyHeaderSealFormat, detectHeaderSealFormat(rlp.List, 1))
 }
 
 func TestWithdrawalsEncoding(t *testing.T) {
@@ -603,3 +643,32 @@ func TestCopyTxs(t *testing.T) {
 	copies := CopyTxs(txs)
 	assert.Equal(t, txs, copies)
 }
+
+func TestHeaderJSONForkFields(t *testing.T) {
+	var fields map[string]interface{}
+
+	h := &Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+	enc, err := json.Marshal(h)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(enc, &fields))
+	for _, k := range []string{"baseFeePerGas", "withdrawalsRoot", "blobGasUsed", "excessBlobGas", "parentBeaconBlockRoot", "requestsRoot"} {
+		assert.NotContains(t, fields, k, "pre-fork field must be absent")
+	}
+
+	var zero uint64
+	h.BaseFee, h.WithdrawalsHash, h.ParentBeaconBlockRoot, h.RequestsRoot = big.NewInt(7), &EmptyRootHash, &EmptyRootHash, &EmptyRootHash
+	h.BlobGasUsed, h.ExcessBlobGas = &zero, &zero
+	enc, err = json.Marshal(h)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(enc, &fields))
+	assert.Equal(t, "0x7", fields["baseFeePerGas"])
+	assert.Equal(t, "0x0", fields["blobGasUsed"])
+	assert.Equal(t, "0x0", fields["excessBlobGas"])
+	assert.Contains(t, fields, "withdrawalsRoot")
+	assert.Contains(t, fields, "parentBeaconBlockRoot")
+	assert.Contains(t, fields, "requestsRoot")
+
+	var dec Header
+	require.NoError(t, json.Unmarshal(enc, &dec))
+	require.Equal(t, h.Hash(), dec.Hash())
+}
@@ -35,11 +35,12 @@ func (h Header) MarshalJSON() ([]byte, error) {
 		Nonce                 BlockNonce       `json:"nonce"`
 		AuRaStep              uint64
 		AuRaSeal              []byte
-		BaseFee               *hexutil.Big    `json:"baseFeePerGas"`
-		WithdrawalsHash       *common.Hash    `json:"withdrawalsRoot"`
-		BlobGasUsed           *hexutil.Uint64 `json:"blobGasUsed"`
-		ExcessBlobGas         *hexutil.Uint64 `json:"excessBlobGas"`
-		ParentBeaconBlockRoot *common.Hash    `json:"parentBeaconBlockRoot"`
+		BaseFee               *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+		WithdrawalsHash       *common.Hash    `json:"withdrawalsRoot,omitempty"`
+		BlobGasUsed           *hexutil.Uint64 `json:"blobGasUsed,omitempty"`
+		ExcessBlobGas         *hexutil.Uint64 `json:"excessBlobGas,omitempty"`
+		ParentBeaconBlockRoot *common.Hash    `json:"parentBeaconBlockRoot,omitempty"`
+		RequestsRoot          *common.Hash    `json:"requestsRoot,omitempty"`
 		Verkle                bool
 		VerkleProof           []byte
 		VerkleKeyVals         []verkle.KeyValuePair
@@ -68,6 +69,7 @@ func (h Header) MarshalJSON() ([]byte, error) {
 	enc.BlobGasUsed = (*hexutil.Uint64)(h.BlobGasUsed)
 	enc.ExcessBlobGas = (*hexutil.Uint64)(h.ExcessBlobGas)
 	enc.ParentBeaconBlockRoot = h.ParentBeaconBlockRoot
+	enc.RequestsRoot = h.RequestsRoot
 	enc.Verkle = h.Verkle
 	enc.VerkleProof = h.VerkleProof
 	enc.VerkleKeyVals = h.VerkleKeyVals
@@ -95,11 +97,12 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 		Nonce                 *BlockNonce       `json:"nonce"`
 		AuRaStep              *uint64
 		AuRaSeal              []byte
-		BaseFee               *hexutil.Big    `json:"baseFeePerGas"`
-		WithdrawalsHash       *common.Hash    `json:"withdrawalsRoot"`
-		BlobGasUsed           *hexutil.Uint64 `json:"blobGasUsed"`
-		ExcessBlobGas         *hexutil.Uint64 `json:"excessBlobGas"`
-		ParentBeaconBlockRoot *common.Hash    `json:"parentBeaconBlockRoot"`
+		BaseFee               *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+		WithdrawalsHash       *common.Hash    `json:"withdrawalsRoot,omitempty"`
+		BlobGasUsed           *hexutil.Uint64 `json:"blobGasUsed,omitempty"`
+		ExcessBlobGas         *hexutil.Uint64 `json:"excessBlobGas,omitempty"`
+		ParentBeaconBlockRoot *common.Hash    `json:"parentBeaconBlockRoot,omitempty"`
+		RequestsRoot          *common.Hash    `json:"requestsRoot,omitempty"`
 		Verkle                *bool
 		VerkleProof           []byte
 		VerkleKeyVals         []verkle.KeyValuePair
@@ -186,6 +189,9 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 	if dec.ParentBeaconBlockRoot != nil {
 		h.ParentBeaconBlockRoot = dec.ParentBeaconBlockRoot
 	}
+	if dec.RequestsRoot != nil {
+		h.RequestsRoot = dec.RequestsRoot
+	}
 	if dec.Verkle != nil {
 		h.Verkle = *dec.Verkle
 	}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	rlp2 "github.com/ledgerwatch/erigon-lib/rlp"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// HeaderSealFormat encodes the chain specific seal fields of a header - the ones which take
+// place of MixDigest & Nonce in the header RLP (e.g. AuRa step & signature).
+// Chain spec only selects one of the formats compiled into the binary by name (see
+// chain.Config.GetHeaderSeal): fields of a new format are Go code registered by
+// RegisterHeaderSealFormat, not something a chain spec can declare.
+type HeaderSealFormat interface {
+	Name() string
+	// InUse reports whether the seal fields of this format are set in the header
+	InUse(h *Header) bool
+	// Detect reports whether the first seal item of an encoded header belongs to this format.
+	// Headers are decoded without chain context, so formats must be distinguishable by the item itself.
+	Detect(kind rlp.Kind, size uint64) bool
+	EncodingSize(h *Header) int
+	EncodeRLP(h *Header, w io.Writer, b []byte) error
+	DecodeRLP(h *Header, s *rlp.Stream) error
+}
+
+// headerSealFormats - registered formats except the default one, in order of registration.
+// Detect and InUse are tried in this order, the default format is the fallback.
+var headerSealFormats []HeaderSealFormat
+
+var defaultHeaderSealFormat HeaderSealFormat = ethashSeal{}
+
+// legacyHeaderSealFormat - decoding fallback for seals detected by no format: header RLP used to
+// decode everything but a 32 bytes MixDigest as AuRa seal, and still does
+var legacyHeaderSealFormat HeaderSealFormat = auraSeal{}
+
+func init() {
+	RegisterHeaderSealFormat(auraSeal{})
+}
+
+// RegisterHeaderSealFormat makes format available to header RLP code. Not thread-safe,
+// must be called from init().
+func RegisterHeaderSealFormat(format HeaderSealFormat) {
+	if _, ok := HeaderSealFormatByName(format.Name()); ok {
+		panic(fmt.Sprintf("header seal format %s is already registered", format.Name()))
+	}
+	headerSealFormats = append(headerSealFormats, format)
+}
+
+// HeaderSealFormatByName returns registered format
+func HeaderSealFormatByName(name string) (HeaderSealFormat, bool) {
+	if name == defaultHeaderSealFormat.Name() {
+		return defaultHeaderSealFormat, true
+	}
+	for _, f := range headerSealFormats {
+		if f.Name() == name {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// VerifyHeaderSeal checks that header uses the seal format declared by the chain config. Engines call it
+// before decoding the seal fields (see ethash.verifyHeader); AuRa doesn't verify seals and accepts unsealed headers.
+func VerifyHeaderSeal(config *chain.Config, h *Header) error {
+	name := config.GetHeaderSeal()
+	format, ok := HeaderSealFormatByName(name)
+	if !ok {
+		return fmt.Errorf("unknown header seal format: %s", name)
+	}
+	if used := headerSealFormatOf(h); used != format {
+		return fmt.Errorf("invalid header seal: chain uses %s, header %v has %s", name, h.Number, used.Name())
+	}
+	return nil
+}
+
+func headerSealFormatOf(h *Header) HeaderSealFormat {
+	for _, f := range headerSealFormats {
+		if f.InUse(h) {
+			return f
+		}
+	}
+	return defaultHeaderSealFormat
+}
+
+func detectHeaderSealFormat(kind rlp.Kind, size uint64) HeaderSealFormat {
+	if defaultHeaderSealFormat.Detect(kind, size) {
+		return defaultHeaderSealFormat
+	}
+	for _, f := range headerSealFormats {
+		if f.Detect(kind, size) {
+			return f
+		}
+	}
+	return legacyHeaderSealFormat
+}
+
+// ethashSeal - MixDigest & Nonce, used by all chains but AuRa ones
+type ethashSeal struct{}
+
+func (ethashSeal) Name() string                        { return chain.HeaderSealEthash }
+func (ethashSeal) InUse(h *Header) bool                { return true }
+func (ethashSeal) Detect(_ rlp.Kind, size uint64) bool { return size == 32 }
+func (ethashSeal) EncodingSize(h *Header) int {
+	return 33 /* MixDigest */ + 9 /* BlockNonce */
+}
+
+func (ethashSeal) EncodeRLP(h *Header, w io.Writer, b []byte) error {
+	b[0] = 128 + 32
+	if _, err := w.Write(b[:1]); err != nil {
+		return err
+	}
+	if _, err := w.Write(h.MixDigest.Bytes()); err != nil {
+		return err
+	}
+	b[0] = 128 + 8
+	if _, err := w.Write(b[:1]); err != nil {
+		return err
+	}
+	if _, err := w.Write(h.Nonce[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ethashSeal) DecodeRLP(h *Header, s *rlp.Stream) error {
+	b, err := s.Bytes()
+	if err != nil {
+		return fmt.Errorf("read MixDigest: %w", err)
+	}
+	if len(b) != 32 {
+		return fmt.Errorf("wrong size for MixDigest: %d", len(b))
+	}
+	copy(h.MixDigest[:], b)
+	if b, err = s.Bytes(); err != nil {
+		return fmt.Errorf("read Nonce: %w", err)
+	}
+	if len(b) != 8 {
+		return fmt.Errorf("wrong size for Nonce: %d", len(b))
+	}
+	copy(h.Nonce[:], b)
+	return nil
+}
+
+// auraSeal - AuRaStep & AuRaSeal (step number and validator signature)
+type auraSeal struct{}
+
+func (auraSeal) Name() string         { return chain.HeaderSealAuRa }
+func (auraSeal) InUse(h *Header) bool { return len(h.AuRaSeal) > 0 }
+func (auraSeal) Detect(kind rlp.Kind, size uint64) bool {
+	return kind != rlp.List && size <= 8 // AuRaStep is an integer, MixDigest is 32 bytes
+}
+func (auraSeal) EncodingSize(h *Header) int {
+	return 1 + rlp.IntLenExcludingHead(h.AuRaStep) + rlp2.ListPrefixLen(len(h.AuRaSeal)) + len(h.AuRaSeal)
+}
+
+func (auraSeal) EncodeRLP(h *Header, w io.Writer, b []byte) error {
+	if err := rlp.EncodeInt(h.AuRaStep, w, b); err != nil {
+		return err
+	}
+	return rlp.EncodeString(h.AuRaSeal, w, b)
+}
+
+func (auraSeal) DecodeRLP(h *Header, s *rlp.Stream) (err error) {
+	if h.AuRaStep, err = s.Uint(); err != nil {
+		return fmt.Errorf("read AuRaStep: %w", err)
+	}
+	if h.AuRaSeal, err = s.Bytes(); err != nil {
+		return fmt.Errorf("read AuRaSeal: %w", err)
+	}
+	return nil
+}
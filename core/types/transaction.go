@@ -51,6 +51,8 @@ const (
 	AccessListTxType
 	DynamicFeeTxType
 	BlobTxType
+	_                        // 0x04 is reserved for EIP-7702 set code transactions
+	AccountAbstractionTxType // RIP-7560, experimental
 )
 
 // Transaction is an Ethereum transaction.
@@ -193,6 +195,8 @@ func UnmarshalTransactionFromBinary(data []byte, blobTxnsAreWrappedWithBlobs boo
 		} else {
 			t = &BlobTx{}
 		}
+	case AccountAbstractionTxType:
+		t = &AccountAbstractionTransaction{}
 	default:
 		if data[0] >= 0x80 {
 			// Tx is type legacy which is RLP encoded
@@ -73,6 +73,7 @@ func MakeSigner(config *chain.Config, blockNumber uint64, blockTime uint64) *Sig
 		// Only allow malleable transactions in Frontier
 		signer.malleable = true
 	}
+	signer.accountAbstraction = config.IsRip7560(blockTime)
 	return &signer
 }
 
@@ -100,6 +101,9 @@ func LatestSigner(config *chain.Config) *Signer {
 	signer.chainID.Set(chainId)
 	signer.chainIDMul.Mul(chainId, u256.Num2)
 	if config.ChainID != nil {
+		if config.Rip7560Time != nil {
+			signer.accountAbstraction = true
+		}
 		if config.CancunTime != nil {
 			signer.blob = true
 		}
@@ -139,6 +143,7 @@ func LatestSignerForChainID(chainID *big.Int) *Signer {
 	signer.accessList = true
 	signer.dynamicFee = true
 	signer.blob = true
+	signer.accountAbstraction = true
 	return &signer
 }
 
@@ -186,11 +191,12 @@ type Signer struct {
 	accessList          bool // Whether this signer should allow transactions with access list, supersedes protected
 	dynamicFee          bool // Whether this signer should allow transactions with base fee and tip (instead of gasprice), supersedes accessList
 	blob                bool // Whether this signer should allow blob transactions
+	accountAbstraction  bool // Whether this signer should allow RIP-7560 account abstraction transactions
 }
 
 func (sg Signer) String() string {
-	return fmt.Sprintf("Signer[chainId=%s,malleable=%t,unprotected=%t,protected=%t,accessList=%t,dynamicFee=%t,blob=%t",
-		&sg.chainID, sg.malleable, sg.unprotected, sg.protected, sg.accessList, sg.dynamicFee, sg.blob)
+	return fmt.Sprintf("Signer[chainId=%s,malleable=%t,unprotected=%t,protected=%t,accessList=%t,dynamicFee=%t,blob=%t,accountAbstraction=%t",
+		&sg.chainID, sg.malleable, sg.unprotected, sg.protected, sg.accessList, sg.dynamicFee, sg.blob, sg.accountAbstraction)
 }
 
 // Sender returns the sender address of the transaction.
@@ -268,6 +274,15 @@ func (sg Signer) SenderWithContext(context *secp256k1.Context, tx Transaction) (
 		// id, add 27 to become equivalent to unprotected Homestead signatures.
 		V.Add(&t.V, u256.Num27)
 		R, S = &t.R, &t.S
+	case *AccountAbstractionTransaction:
+		if !sg.accountAbstraction {
+			return libcommon.Address{}, fmt.Errorf("account abstraction tx is not supported by signer %s", sg)
+		}
+		if t.ChainID == nil || !t.ChainID.Eq(&sg.chainID) {
+			return libcommon.Address{}, ErrInvalidChainId
+		}
+		// Not signed: the sender contract authorizes the transaction during the validation frame
+		return t.SenderAddress, nil
 	default:
 		return libcommon.Address{}, ErrTxTypeNotSupported
 	}
@@ -325,7 +340,8 @@ func (sg Signer) Equal(other Signer) bool {
 		sg.protected == other.protected &&
 		sg.accessList == other.accessList &&
 		sg.dynamicFee == other.dynamicFee &&
-		sg.blob == other.blob
+		sg.blob == other.blob &&
+		sg.accountAbstraction == other.accountAbstraction
 }
 
 func decodeSignature(sig []byte) (r, s, v *uint256.Int) {
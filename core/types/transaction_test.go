@@ -869,3 +869,78 @@ func TestTrailingBytes(t *testing.T) {
 		panic("Malicious transaction has not errored!") // @audit this panic is occurs
 	}
 }
+
+func TestAccountAbstractionTxEncodeDecode(t *testing.T) {
+	paymaster := libcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	tx := &AccountAbstractionTransaction{
+		ChainID:                     uint256.NewInt(1),
+		Nonce:                       7,
+		SenderAddress:               libcommon.HexToAddress("0x1000000000000000000000000000000000000001"),
+		Paymaster:                   &paymaster,
+		PaymasterData:               []byte{0x01, 0x02},
+		ExecutionData:               []byte{0x00, 0xaa, 0xbb},
+		BuilderFee:                  uint256.NewInt(3),
+		Tip:                         uint256.NewInt(1_000_000_000),
+		FeeCap:                      uint256.NewInt(30_000_000_000),
+		ValidationGasLimit:          100_000,
+		PaymasterValidationGasLimit: 50_000,
+		PostOpGasLimit:              20_000,
+		Gas:                         200_000,
+		AccessList:                  types2.AccessList{{Address: paymaster, StorageKeys: []libcommon.Hash{{0x01}}}},
+		AuthorizationData:           []byte{0xde, 0xad},
+	}
+
+	var buf bytes.Buffer
+	if err := tx.MarshalBinary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, crypto.Keccak256Hash(buf.Bytes()), tx.Hash())
+	assert.Equal(t, tx.EncodingSize(), buf.Len())
+
+	parsed, err := encodeDecodeBinary(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := assertEqual(tx, parsed); err != nil {
+		t.Fatal(err)
+	}
+	aaTx := parsed.(*AccountAbstractionTransaction)
+	assert.Nil(t, aaTx.Deployer)
+	assert.Equal(t, paymaster, aaTx.GasPayer())
+	assert.Equal(t, fixedgas.AABaseGas+370_000, aaTx.GetGas())
+
+	// sender is a part of the transaction, signer only needs to support the type
+	from, err := parsed.Sender(*LatestSignerForChainID(big.NewInt(1)))
+	assert.NoError(t, err)
+	assert.Equal(t, tx.SenderAddress, from)
+	_, err = parsed.Sender(*LatestSignerForChainID(big.NewInt(2)))
+	assert.ErrorIs(t, err, ErrInvalidChainId)
+
+	// txpool parsing
+	ctx := libtypes.NewTxParseContext(*uint256.NewInt(1))
+	slot := &libtypes.TxSlot{}
+	sender := make([]byte, 20)
+	_, err = ctx.ParseTransaction(buf.Bytes(), 0, slot, sender, false /* hasEnvelope */, false /* wrappedWithBlobs */, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tx.SenderAddress[:], sender)
+	assert.Equal(t, libcommon.Hash(slot.IDHash), tx.Hash())
+	assert.Equal(t, tx.GetGas(), slot.Gas)
+	assert.Equal(t, tx.Nonce, slot.Nonce)
+	assert.Equal(t, &paymaster, slot.Paymaster)
+	assert.Equal(t, 2+3+2, slot.DataLen)
+	assert.Equal(t, 1, slot.AlAddrCount)
+	assert.Equal(t, 1, slot.AlStorCount)
+
+	// extra field after the last one of the body must be rejected
+	body, _, err := rlp.SplitList(buf.Bytes()[1:])
+	assert.NoError(t, err)
+	var extended bytes.Buffer
+	extended.WriteByte(AccountAbstractionTxType)
+	assert.NoError(t, EncodeStructSizePrefix(len(body)+1, &extended, make([]byte, 9)))
+	extended.Write(body)
+	extended.WriteByte(0x80)
+	_, err = ctx.ParseTransaction(extended.Bytes(), 0, &libtypes.TxSlot{}, sender, false /* hasEnvelope */, false /* wrappedWithBlobs */, nil)
+	assert.ErrorIs(t, err, libtypes.ErrParseTxn)
+}
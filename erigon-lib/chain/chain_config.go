@@ -69,6 +69,9 @@ type Config struct {
 	PragueTime   *big.Int `json:"pragueTime,omitempty"`
 	OsakaTime    *big.Int `json:"osakaTime,omitempty"`
 
+	// (Optional, experimental) RIP-7560: Native Account Abstraction
+	Rip7560Time *big.Int `json:"rip7560Time,omitempty"`
+
 	// Optional EIP-4844 parameters
 	MinBlobGasPrice            *uint64 `json:"minBlobGasPrice,omitempty"`
 	MaxBlobGasPerBlock         *uint64 `json:"maxBlobGasPerBlock,omitempty"`
@@ -89,6 +92,11 @@ type Config struct {
 
 	Bor     BorConfig       `json:"-"`
 	BorJSON json.RawMessage `json:"bor,omitempty"`
+
+	// (Optional) name of the header seal format, i.e. fields which take place of MixDigest & Nonce
+	// in the header RLP. Must be one of the formats compiled into core/types (see HeaderSealFormat),
+	// headers of a chain with an unknown format fail verification. Derived from the consensus engine if not set.
+	HeaderSeal string `json:"headerSeal,omitempty"`
 }
 
 type BorConfig interface {
@@ -97,6 +105,9 @@ type BorConfig interface {
 	GetAgraBlock() *big.Int
 	IsNapoli(num uint64) bool
 	GetNapoliBlock() *big.Int
+	GetForkBlocks() []uint64
+	GetForkTimes() []uint64
+	GetBurntContract(num, time uint64) *common.Address
 }
 
 func (c *Config) String() string {
@@ -142,6 +153,18 @@ func (c *Config) getEngine() string {
 	}
 }
 
+// GetHeaderSeal returns the name of the header seal format used by the chain
+func (c *Config) GetHeaderSeal() string {
+	switch {
+	case c.HeaderSeal != "":
+		return c.HeaderSeal
+	case c.Aura != nil:
+		return HeaderSealAuRa
+	default:
+		return HeaderSealEthash
+	}
+}
+
 // IsHomestead returns whether num is either equal to the homestead block or greater.
 func (c *Config) IsHomestead(num uint64) bool {
 	return isForked(c.HomesteadBlock, num)
@@ -242,7 +265,17 @@ func (c *Config) IsOsaka(time uint64) bool {
 	return isForked(c.OsakaTime, time)
 }
 
-func (c *Config) GetBurntContract(num uint64) *common.Address {
+// IsRip7560 returns whether time is either equal to the RIP-7560 activation time or greater.
+func (c *Config) IsRip7560(time uint64) bool {
+	return isForked(c.Rip7560Time, time)
+}
+
+func (c *Config) GetBurntContract(num, time uint64) *common.Address {
+	if c.Bor != nil {
+		if addr := c.Bor.GetBurntContract(num, time); addr != nil {
+			return addr
+		}
+	}
 	if len(c.BurntContract) == 0 {
 		return nil
 	}
@@ -508,6 +541,7 @@ type Rules struct {
 	IsIstanbul, IsBerlin, IsLondon, IsShanghai        bool
 	IsCancun, IsNapoli                                bool
 	IsPrague, IsOsaka                                 bool
+	IsRip7560                                         bool
 	IsAura                                            bool
 }
 
@@ -534,6 +568,7 @@ func (c *Config) Rules(num uint64, time uint64) *Rules {
 		IsNapoli:           c.IsNapoli(num),
 		IsPrague:           c.IsPrague(time),
 		IsOsaka:            c.IsOsaka(time),
+		IsRip7560:          c.IsRip7560(time),
 		IsAura:             c.Aura != nil,
 	}
 }
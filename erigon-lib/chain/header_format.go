@@ -0,0 +1,87 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Names of the header seal formats known to core/types
+const (
+	HeaderSealEthash = "ethash" // MixDigest & Nonce
+	HeaderSealAuRa   = "aura"   // AuRaStep & AuRaSeal
+)
+
+// ExtraDataLayout describes how PoA engines (e.g. bor) split the header extra-data:
+// Vanity + engine specific payload (e.g. bor validator bytes) + Seal (signer signature).
+type ExtraDataLayout struct {
+	VanityLength int `json:"vanityLength"`
+	SealLength   int `json:"sealLength"`
+}
+
+// DefaultExtraDataLayout is the layout of clique and bor
+var DefaultExtraDataLayout = ExtraDataLayout{VanityLength: 32, SealLength: 65}
+
+// MaxExtraDataPartLength bounds the vanity and the seal of ExtraDataLayout - a signature or a vanity
+// of a few KB means a typo in the chain spec
+const MaxExtraDataPartLength = 1024
+
+// Validate checks that the lengths of the layout are sane
+func (l ExtraDataLayout) Validate() error {
+	if l.VanityLength < 0 || l.VanityLength > MaxExtraDataPartLength {
+		return fmt.Errorf("extra-data layout: vanity length %d is out of [0, %d]", l.VanityLength, MaxExtraDataPartLength)
+	}
+	if l.SealLength < 0 || l.SealLength > MaxExtraDataPartLength {
+		return fmt.Errorf("extra-data layout: seal length %d is out of [0, %d]", l.SealLength, MaxExtraDataPartLength)
+	}
+	return nil
+}
+
+func (l *ExtraDataLayout) UnmarshalJSON(data []byte) error {
+	type extraDataLayout ExtraDataLayout // no methods - avoids recursion
+	if err := json.Unmarshal(data, (*extraDataLayout)(l)); err != nil {
+		return err
+	}
+	return l.Validate()
+}
+
+// HasVanity reports whether extra has room for the vanity
+func (l ExtraDataLayout) HasVanity(extra []byte) bool {
+	return len(extra) >= l.VanityLength
+}
+
+// HasSeal reports whether extra has room for both the vanity and the seal
+func (l ExtraDataLayout) HasSeal(extra []byte) bool {
+	return len(extra) >= l.VanityLength+l.SealLength
+}
+
+// Vanity returns the vanity prefix of extra, or nil if extra is too short
+func (l ExtraDataLayout) Vanity(extra []byte) []byte {
+	if !l.HasVanity(extra) {
+		return nil
+	}
+	return extra[:l.VanityLength]
+}
+
+// Payload returns bytes between the vanity and the seal, or nil if extra is too short
+func (l ExtraDataLayout) Payload(extra []byte) []byte {
+	if !l.HasSeal(extra) {
+		return nil
+	}
+	return extra[l.VanityLength : len(extra)-l.SealLength]
+}
+
+// Seal returns the seal suffix of extra, or nil if extra is too short
+func (l ExtraDataLayout) Seal(extra []byte) []byte {
+	if len(extra) < l.SealLength {
+		return nil
+	}
+	return extra[len(extra)-l.SealLength:]
+}
+
+// Unsealed returns extra without the seal suffix, or nil if extra is too short
+func (l ExtraDataLayout) Unsealed(extra []byte) []byte {
+	if len(extra) < l.SealLength {
+		return nil
+	}
+	return extra[:len(extra)-l.SealLength]
+}
@@ -0,0 +1,255 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+)
+
+// BudgetLevel - how close the datadir is to its max size (--datadir.max-size)
+type BudgetLevel int32
+
+const (
+	BudgetOk       BudgetLevel = iota
+	BudgetLow                  // above BudgetLowRatio of max size: snapshot downloads are paused, pruning is prioritized
+	BudgetExceeded             // above max size: also no new snapshot files are produced (Snapshots stage is stopped)
+)
+
+func (l BudgetLevel) String() string {
+	switch l {
+	case BudgetOk:
+		return "ok"
+	case BudgetLow:
+		return "low"
+	case BudgetExceeded:
+		return "exceeded"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	BudgetLowRatio = 0.9
+	// level goes down only when usage drops this much below the threshold - to not flap on every check
+	budgetHysteresisRatio = 0.05
+	budgetCheckInterval   = time.Minute
+	// every Nth check re-reads all directories, others skip the unchanged ones (see dirSizes)
+	budgetFullScanEvery = 10
+)
+
+var (
+	datadirSizeGauge    = metrics.GetOrCreateGauge(`datadir_size_bytes`)
+	datadirMaxSizeGauge = metrics.GetOrCreateGauge(`datadir_max_size_bytes`)
+	datadirBudgetGauge  = metrics.GetOrCreateGauge(`datadir_budget_level`)
+)
+
+// Budget watches the datadir size against the configured max size. Components degrade gracefully
+// (see BudgetLevel) instead of filling the disk - which may corrupt MDBX. A nil Budget means no limit.
+type Budget struct {
+	dir    string
+	logger log.Logger
+
+	maxSize atomic.Uint64 // can be changed at runtime by SetMaxSize (admin_setDatadirMaxSize)
+	used    atomic.Uint64
+	level   atomic.Int32
+
+	checkLock sync.Mutex // serializes checks: levels are compared and listeners notified in order
+	sizes     *dirSizes
+	checks    uint64
+
+	lock      sync.Mutex
+	listeners []func(level BudgetLevel)
+}
+
+func NewBudget(dir string, maxSize uint64, logger log.Logger) *Budget {
+	b := &Budget{dir: dir, logger: logger}
+	b.maxSize.Store(maxSize)
+	datadirMaxSizeGauge.SetUint64(maxSize)
+	return b
+}
+
+func (b *Budget) Level() BudgetLevel {
+	if b == nil {
+		return BudgetOk
+	}
+	return BudgetLevel(b.level.Load())
+}
+
+func (b *Budget) Used() uint64    { return b.used.Load() }
+func (b *Budget) MaxSize() uint64 { return b.maxSize.Load() }
+
+// SetMaxSize changes the max size and re-checks the datadir against it, listeners are notified if the level changes
+func (b *Budget) SetMaxSize(maxSize uint64) error {
+	b.maxSize.Store(maxSize)
+	datadirMaxSizeGauge.SetUint64(maxSize)
+	b.logger.Info("[disk] datadir max size is changed", "max", common.ByteCount(maxSize))
+	return b.Check()
+}
+
+// OnLevelChange registers fn to be called (from the checking goroutine, or SetMaxSize caller) every time the level changes
+func (b *Budget) OnLevelChange(fn func(level BudgetLevel)) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.listeners = append(b.listeners, fn)
+}
+
+// Run checks the datadir size periodically until ctx is cancelled
+func (b *Budget) Run(ctx context.Context) {
+	ticker := time.NewTicker(budgetCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := b.Check(); err != nil {
+			b.logger.Warn("[disk] can't measure datadir size", "dir", b.dir, "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check measures the datadir, updates the level and notifies listeners if it changed
+func (b *Budget) Check() error {
+	b.checkLock.Lock()
+	defer b.checkLock.Unlock()
+	if b.sizes == nil || b.checks%budgetFullScanEvery == 0 {
+		b.sizes = &dirSizes{dirs: map[string]*dirSize{}}
+	}
+	b.checks++
+	used, err := b.sizes.size(b.dir)
+	if err != nil {
+		return err
+	}
+	b.used.Store(used)
+	datadirSizeGauge.SetUint64(used)
+
+	maxSize := b.MaxSize()
+	prev := b.Level()
+	level := nextBudgetLevel(prev, used, maxSize)
+	if level != BudgetOk {
+		b.logger.Warn("[disk] datadir is close to --datadir.max-size", "used", common.ByteCount(used), "max", common.ByteCount(maxSize), "level", level)
+	}
+	if level == prev {
+		return nil
+	}
+	b.level.Store(int32(level))
+	datadirBudgetGauge.SetInt(int(level))
+	if level < prev {
+		b.logger.Info("[disk] datadir size is back within budget", "used", common.ByteCount(used), "max", common.ByteCount(maxSize), "level", level)
+	}
+
+	b.lock.Lock()
+	listeners := b.listeners
+	b.lock.Unlock()
+	for _, fn := range listeners {
+		fn(level)
+	}
+	return nil
+}
+
+func nextBudgetLevel(current BudgetLevel, used, maxSize uint64) BudgetLevel {
+	threshold := func(level BudgetLevel) float64 {
+		switch level {
+		case BudgetExceeded:
+			return float64(maxSize)
+		case BudgetLow:
+			return float64(maxSize) * BudgetLowRatio
+		default:
+			return 0
+		}
+	}
+	level := BudgetOk
+	for l := BudgetExceeded; l > BudgetOk; l-- {
+		limit := threshold(l)
+		if l <= current { // leaving the level requires going below the threshold by the hysteresis
+			limit -= float64(maxSize) * budgetHysteresisRatio
+		}
+		if float64(used) >= limit {
+			level = l
+			break
+		}
+	}
+	return level
+}
+
+// dirSizes - total size of regular files in a dir (recursively), remembered per directory. Snapshot dirs
+// have thousands of immutable files (created as tmp and renamed), stat-ing all of them on every check is
+// wasteful: a directory whose mtime and files size didn't change since the previous read is stable and
+// its files are not re-stat-ed until its mtime changes. A file growing in place (db) keeps its dir unstable.
+// Budget re-creates it every budgetFullScanEvery checks - to catch growth in a stable dir.
+type dirSizes struct {
+	dirs map[string]*dirSize
+}
+
+type dirSize struct {
+	modTime time.Time
+	files   uint64 // regular files directly in the dir
+	subdirs []string
+	stable  bool
+}
+
+func (c *dirSizes) size(dir string) (uint64, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			delete(c.dirs, dir)
+			return 0, nil
+		}
+		return 0, err
+	}
+	cached := c.dirs[dir]
+	if cached == nil || !cached.stable || !cached.modTime.Equal(info.ModTime()) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				delete(c.dirs, dir)
+				return 0, nil
+			}
+			return 0, err
+		}
+		read := &dirSize{modTime: info.ModTime()}
+		for _, e := range entries {
+			if e.IsDir() {
+				read.subdirs = append(read.subdirs, filepath.Join(dir, e.Name()))
+				continue
+			}
+			if !e.Type().IsRegular() {
+				continue
+			}
+			fileInfo, err := e.Info()
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return 0, err
+			}
+			read.files += uint64(fileInfo.Size())
+		}
+		read.stable = cached != nil && cached.modTime.Equal(read.modTime) && cached.files == read.files
+		cached = read
+		c.dirs[dir] = cached
+	}
+
+	total := cached.files
+	for _, subdir := range cached.subdirs {
+		size, err := c.size(subdir)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
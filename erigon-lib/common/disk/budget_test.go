@@ -0,0 +1,93 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextBudgetLevel(t *testing.T) {
+	const maxSize = 1000
+	cases := []struct {
+		current BudgetLevel
+		used    uint64
+		want    BudgetLevel
+	}{
+		{BudgetOk, 0, BudgetOk},
+		{BudgetOk, 899, BudgetOk},
+		{BudgetOk, 900, BudgetLow},
+		{BudgetOk, 1000, BudgetExceeded},
+		{BudgetLow, 851, BudgetLow}, // hysteresis
+		{BudgetLow, 849, BudgetOk},
+		{BudgetExceeded, 951, BudgetExceeded},
+		{BudgetExceeded, 949, BudgetLow},
+		{BudgetExceeded, 100, BudgetOk},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.want, nextBudgetLevel(c.current, c.used, maxSize), "current=%s used=%d", c.current, c.used)
+	}
+}
+
+func TestBudgetCheck(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "snapshots"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), make([]byte, 100), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshots", "b"), make([]byte, 850), 0644))
+
+	var nilBudget *Budget
+	require.Equal(t, BudgetOk, nilBudget.Level())
+
+	b := NewBudget(dir, 1000, log.New())
+	var levels []BudgetLevel
+	b.OnLevelChange(func(level BudgetLevel) { levels = append(levels, level) })
+
+	require.NoError(t, b.Check())
+	require.Equal(t, uint64(950), b.Used())
+	require.Equal(t, BudgetLow, b.Level())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c"), make([]byte, 50), 0644))
+	require.NoError(t, b.Check())
+	require.Equal(t, BudgetExceeded, b.Level())
+
+	require.NoError(t, b.Check()) // no change - no notification
+	require.NoError(t, os.Remove(filepath.Join(dir, "snapshots", "b")))
+	require.NoError(t, b.Check())
+	require.Equal(t, BudgetOk, b.Level())
+	require.Equal(t, []BudgetLevel{BudgetLow, BudgetExceeded, BudgetOk}, levels)
+
+	// lowered at runtime - re-checked immediately
+	require.NoError(t, b.SetMaxSize(100))
+	require.Equal(t, uint64(100), b.MaxSize())
+	require.Equal(t, BudgetExceeded, b.Level())
+	require.Equal(t, []BudgetLevel{BudgetLow, BudgetExceeded, BudgetOk, BudgetExceeded}, levels)
+}
+
+func TestDirSizes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "snapshots"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshots", "a"), make([]byte, 100), 0644))
+
+	sizes := &dirSizes{dirs: map[string]*dirSize{}}
+	size := func() uint64 {
+		t.Helper()
+		size, err := sizes.size(dir)
+		require.NoError(t, err)
+		return size
+	}
+	require.Equal(t, uint64(100), size())
+
+	// grows in place: dir mtime doesn't change, but the dir is not stable yet - re-read
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshots", "a"), make([]byte, 200), 0644))
+	require.Equal(t, uint64(200), size())
+	require.Equal(t, uint64(200), size())
+	require.True(t, sizes.dirs[filepath.Join(dir, "snapshots")].stable)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "snapshots", "b"), make([]byte, 50), 0644)) // dir mtime changes
+	require.Equal(t, uint64(250), size())
+
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "snapshots")))
+	require.Equal(t, uint64(0), size())
+}
@@ -36,4 +36,7 @@ const (
 	BlobSize                       = FieldElementsPerBlob * 32
 	BlobGasPerBlob          uint64 = 0x20000
 	DefaultMaxBlobsPerBlock uint64 = 6 // lower for Gnosis
+
+	// RIP-7560: Native Account Abstraction (experimental)
+	AABaseGas uint64 = 15000 // Per account abstraction transaction, replaces TxGas
 )
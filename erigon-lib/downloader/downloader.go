@@ -94,6 +94,7 @@ type Downloader struct {
 	webDownloadInfo map[string]webDownloadInfo
 	downloading     map[string]*downloadInfo
 	downloadLimit   *rate.Limit
+	paused          atomic.Bool // by SetPaused
 }
 
 type downloadInfo struct {
@@ -1060,6 +1061,15 @@ func (d *Downloader) mainLoop(silent bool) error {
 			default:
 			}
 
+			if d.paused.Load() { // don't start new downloads
+				select {
+				case <-d.ctx.Done():
+					return
+				case <-time.After(10 * time.Second):
+					continue
+				}
+			}
+
 			d.lock.RLock()
 			webDownloadInfoLen := len(d.webDownloadInfo)
 			d.lock.RUnlock()
@@ -1496,6 +1506,9 @@ func getWebpeerTorrentInfo(ctx context.Context, downloadUrl *url.URL) (*metainfo
 func (d *Downloader) torrentDownload(t *torrent.Torrent, statusChan chan downloadStatus) {
 	d.lock.Lock()
 	d.downloading[t.Name()] = &downloadInfo{torrent: t}
+	if !d.paused.Load() { // under lock - SetPaused toggles d.downloading under the same lock
+		t.AllowDataDownload()
+	}
 	d.lock.Unlock()
 
 	d.wg.Add(1)
@@ -1505,8 +1518,6 @@ func (d *Downloader) torrentDownload(t *torrent.Torrent, statusChan chan downloa
 
 		downloadStarted := time.Now()
 
-		t.AllowDataDownload()
-
 		select {
 		case <-d.ctx.Done():
 			return
@@ -1539,7 +1550,9 @@ func (d *Downloader) torrentDownload(t *torrent.Torrent, statusChan chan downloa
 			case <-time.After(10 * time.Second):
 				bytesRead := t.Stats().BytesReadData
 
-				if lastRead-bytesRead.Int64() == 0 {
+				if d.paused.Load() { // not idle - just not allowed to download
+					idleCount = 0
+				} else if lastRead-bytesRead.Int64() == 0 {
 					idleCount++
 				} else {
 					lastRead = bytesRead.Int64()
@@ -2643,6 +2656,28 @@ func (d *Downloader) BuildTorrentFilesIfNeed(ctx context.Context, chain string,
 	_, err := BuildTorrentFilesIfNeed(ctx, d.cfg.Dirs, d.torrentFS, chain, ignore)
 	return err
 }
+
+// SetPaused stops (or resumes) downloading of data: new downloads are not started and active ones
+// stop requesting pieces. Seeding of complete files continues. Used when the datadir runs out of its size budget.
+func (d *Downloader) SetPaused(paused bool) {
+	if d.paused.Swap(paused) == paused {
+		return
+	}
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	for _, info := range d.downloading {
+		if info.torrent == nil {
+			continue
+		}
+		if paused {
+			info.torrent.DisallowDataDownload()
+		} else {
+			info.torrent.AllowDataDownload()
+		}
+	}
+	d.logger.Info("[snapshots] downloads", "paused", paused, "active", len(d.downloading))
+}
+
 func (d *Downloader) Stats() AggStats {
 	d.lock.RLock()
 	defer d.lock.RUnlock()
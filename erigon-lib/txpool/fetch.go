@@ -373,7 +373,7 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 		if len(txs.Txs) == 0 {
 			return nil
 		}
-		f.pool.AddRemoteTxs(ctx, txs)
+		f.pool.AddRemoteTxs(ctx, txs, req.PeerId)
 	default:
 		defer f.logger.Trace("[txpool] dropped p2p message", "id", req.Id)
 	}
@@ -381,6 +381,18 @@ func (f *Fetch) handleInboundMessage(ctx context.Context, req *sentry.InboundMes
 	return nil
 }
 
+// PenalizePeer disconnects the peer, for example, if it sends transactions failing validation
+func (f *Fetch) PenalizePeer(peerID types2.PeerID) {
+	for _, sentryClient := range f.sentryClients {
+		if !sentryClient.Ready() {
+			continue
+		}
+		if _, err := sentryClient.PenalizePeer(f.ctx, &sentry.PenalizePeerRequest{PeerId: peerID, Penalty: sentry.PenaltyKind_Kick}); err != nil {
+			f.logger.Debug("[txpool.fetch] penalize peer", "err", err)
+		}
+	}
+}
+
 func (f *Fetch) receivePeerLoop(sentryClient sentry.SentryClient) {
 	for {
 		select {
@@ -59,6 +59,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/types"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
 	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/sync/errgroup"
 )
 
 const DefaultBlockGasLimit = uint64(30000000)
@@ -87,7 +88,7 @@ type Pool interface {
 	ValidateSerializedTxn(serializedTxn []byte) error
 
 	// Handle 3 main events - new remote txs from p2p, new local txs from RPC, new blocks from execution layer
-	AddRemoteTxs(ctx context.Context, newTxs types.TxSlots)
+	AddRemoteTxs(ctx context.Context, newTxs types.TxSlots, peerID types.PeerID)
 	AddLocalTxs(ctx context.Context, newTxs types.TxSlots, tx kv.Tx) ([]txpoolcfg.DiscardReason, error)
 	OnNewBlock(ctx context.Context, stateChanges *remote.StateChangeBatch, unwindTxs, unwindBlobTxs, minedTxs types.TxSlots, tx kv.Tx) error
 	// IdHashKnown check whether transaction with given Id hash is known to the pool
@@ -230,6 +231,12 @@ type TxPool struct {
 	isPostCancun            atomic.Bool
 	maxBlobsPerBlock        uint64
 	feeCalculator           FeeCalculator
+	aaValidator             AAValidator
+	aaValidationQueue       map[string]*metaTx  // RIP-7560 transactions which became next to execute, see validateQueuedAATxs
+	aaRemoteQueue           []aaRemoteTx        // remote RIP-7560 transactions waiting for validateRemoteAATxs
+	aaRemoteQueued          map[string]struct{} // hashes of aaRemoteQueue
+	aaRemoteByPeer          map[[64]byte]int    // amount of aaRemoteQueue transactions by peer
+	onBadPeer               func(peerID types.PeerID)
 	logger                  log.Logger
 }
 
@@ -237,9 +244,43 @@ type FeeCalculator interface {
 	CurrentFees(chainConfig *chain.Config, db kv.Getter) (baseFee uint64, blobFee uint64, minBlobGasPrice, blockGasLimit uint64, err error)
 }
 
+// AAValidator runs validation frames of RIP-7560 transaction (see txpoolcfg.Config.AccountAbstraction) on the latest state.
+// Must fail transactions whose validation frames ask for more than gasCap and stop execution when ctx is done.
+// Called concurrently.
+type AAValidator interface {
+	ValidateAA(ctx context.Context, txnRlp []byte, gasCap uint64) error
+}
+
+const (
+	// aaValidationGasCap - max gas of validation frames (account and paymaster) which txpool runs for a RIP-7560 transaction
+	aaValidationGasCap = 1_000_000
+	// aaValidationTimeout - max time of validation frames of a RIP-7560 transaction
+	aaValidationTimeout = 100 * time.Millisecond
+
+	// Validation frames of remote and just promoted transactions run in batches (every ProcessRemoteTxsEvery) by
+	// aaValidationWorkers goroutines. A batch is limited by gas - aaValidationBatchSize transactions
+	// of aaValidationGasCap each, and time - transactions which didn't start in aaValidationBatchTimeout wait for
+	// the next batch. Senders are not authenticated before validation, so remote transactions are limited by peer.
+	aaValidationWorkers        = 4
+	aaValidationBatchSize      = 16
+	aaValidationBatchTimeout   = 500 * time.Millisecond
+	aaValidationPeerBatchLimit = 2    // max remote transactions of a peer in a batch
+	aaRemoteQueueLimit         = 1024 // max remote transactions waiting for validation, more are dropped (not discarded)
+	aaRemoteQueuePeerLimit     = 32   // max remote transactions of a peer waiting for validation
+)
+
+// errAAValidationSkipped - validation frames didn't run (or were interrupted) because the batch is over
+var errAAValidationSkipped = errors.New("account abstraction validation batch is over")
+
+type aaRemoteTx struct {
+	txn    *types.TxSlot
+	sender common.Address
+	peerID types.PeerID
+}
+
 func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config, cache kvcache.Cache,
 	chainID uint256.Int, shanghaiTime, agraBlock, cancunTime *big.Int, maxBlobsPerBlock uint64,
-	feeCalculator FeeCalculator, logger log.Logger,
+	feeCalculator FeeCalculator, aaValidator AAValidator, logger log.Logger,
 ) (*TxPool, error) {
 	localsHistory, err := simplelru.NewLRU[string, struct{}](10_000, nil)
 	if err != nil {
@@ -249,12 +290,12 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 	if err != nil {
 		return nil, err
 	}
-
 	byNonce := &BySenderAndNonce{
 		tree:              btree.NewG[*metaTx](32, SortByNonceLess),
 		search:            &metaTx{Tx: &types.TxSlot{}},
 		senderIDTxnCount:  map[uint64]int{},
 		senderIDBlobCount: map[uint64]uint64{},
+		paymasterTxs:      map[common.Address]*sponsoredTxs{},
 	}
 	tracedSenders := make(map[common.Address]struct{})
 	for _, sender := range cfg.TracedSenders {
@@ -286,6 +327,10 @@ func New(newTxs chan types.Announcements, coreDB kv.RoDB, cfg txpoolcfg.Config,
 		minedBlobTxsByHash:      map[string]*metaTx{},
 		maxBlobsPerBlock:        maxBlobsPerBlock,
 		feeCalculator:           feeCalculator,
+		aaValidator:             aaValidator,
+		aaValidationQueue:       map[string]*metaTx{},
+		aaRemoteQueued:          map[string]struct{}{},
+		aaRemoteByPeer:          map[[64]byte]int{},
 		logger:                  logger,
 	}
 
@@ -450,7 +495,7 @@ func (p *TxPool) OnNewBlock(ctx context.Context, stateChanges *remote.StateChang
 		return err
 	}
 
-	_, unwindTxs, err = p.validateTxs(&unwindTxs, cacheView)
+	_, unwindTxs, err = p.validateTxs(&unwindTxs, nil, cacheView)
 
 	if err != nil {
 		return err
@@ -535,7 +580,7 @@ func (p *TxPool) processRemoteTxs(ctx context.Context) error {
 		return err
 	}
 
-	_, newTxs, err := p.validateTxs(p.unprocessedRemoteTxs, cacheView)
+	_, newTxs, err := p.validateTxs(p.unprocessedRemoteTxs, nil, cacheView)
 	if err != nil {
 		return err
 	}
@@ -800,7 +845,7 @@ func (p *TxPool) CountContent() (int, int, int) {
 	defer p.lock.Unlock()
 	return p.pending.Len(), p.baseFee.Len(), p.queued.Len()
 }
-func (p *TxPool) AddRemoteTxs(_ context.Context, newTxs types.TxSlots) {
+func (p *TxPool) AddRemoteTxs(_ context.Context, newTxs types.TxSlots, peerID types.PeerID) {
 	if p.cfg.NoGossip {
 		// if no gossip, then
 		// disable adding remote transactions
@@ -812,6 +857,10 @@ func (p *TxPool) AddRemoteTxs(_ context.Context, newTxs types.TxSlots) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	for i, txn := range newTxs.Txs {
+		if txn.Type == types.AccountAbstractionTxType && p.cfg.AccountAbstraction && p.aaValidator != nil {
+			p.queueRemoteAATxLocked(aaRemoteTx{txn: txn, sender: newTxs.Senders.AddressAt(i), peerID: peerID})
+			continue
+		}
 		hashS := string(txn.IDHash[:])
 		_, ok := p.unprocessedRemoteByHash[hashS]
 		if ok {
@@ -839,6 +888,9 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 			return txpoolcfg.InitCodeTooLarge
 		}
 	}
+	if txn.Type == types.AccountAbstractionTxType && (!p.cfg.AccountAbstraction || p.aaValidator == nil) {
+		return txpoolcfg.TypeNotActivated
+	}
 	if txn.Type == types.BlobTxType {
 		if !p.isCancun() {
 			return txpoolcfg.TypeNotActivated
@@ -895,21 +947,24 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 		}
 		return txpoolcfg.UnderPriced
 	}
-	gas, reason := txpoolcfg.CalcIntrinsicGas(uint64(txn.DataLen), uint64(txn.DataNonZeroLen), nil, txn.Creation, true, true, isShanghai)
-	if txn.Traced {
-		p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas idHash=%x gas=%d", txn.IDHash, gas))
-	}
-	if reason != txpoolcfg.Success {
+	// Intrinsic gas of RIP-7560 transaction is paid from the validation gas limit, checked by the validation
+	if txn.Type != types.AccountAbstractionTxType {
+		gas, reason := txpoolcfg.CalcIntrinsicGas(uint64(txn.DataLen), uint64(txn.DataNonZeroLen), nil, txn.Creation, true, true, isShanghai)
 		if txn.Traced {
-			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas calculated failed idHash=%x reason=%s", txn.IDHash, reason))
+			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas idHash=%x gas=%d", txn.IDHash, gas))
 		}
-		return reason
-	}
-	if gas > txn.Gas {
-		if txn.Traced {
-			p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas > txn.gas idHash=%x gas=%d, txn.gas=%d", txn.IDHash, gas, txn.Gas))
+		if reason != txpoolcfg.Success {
+			if txn.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas calculated failed idHash=%x reason=%s", txn.IDHash, reason))
+			}
+			return reason
+		}
+		if gas > txn.Gas {
+			if txn.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: validateTx intrinsic gas > txn.gas idHash=%x gas=%d, txn.gas=%d", txn.IDHash, gas, txn.Gas))
+			}
+			return txpoolcfg.IntrinsicGas
 		}
-		return txpoolcfg.IntrinsicGas
 	}
 	if !isLocal && uint64(p.all.count(txn.SenderID)) > p.cfg.AccountSlots {
 		if txn.Traced {
@@ -926,7 +981,16 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 		}
 		return txpoolcfg.NonceTooLow
 	}
-	// Transactor should have enough funds to cover the costs
+	// Transactor (or RIP-7560 paymaster) should have enough funds to cover the costs
+	if txn.Paymaster != nil {
+		var err error
+		if _, senderBalance, err = accountInfo(stateCache, *txn.Paymaster); err != nil {
+			if txn.Traced {
+				p.logger.Info(fmt.Sprintf("TX TRACING: validateTx paymaster state idHash=%x err=%s", txn.IDHash, err))
+			}
+			return txpoolcfg.PaymasterStateErr
+		}
+	}
 	total := requiredBalance(txn)
 	if senderBalance.Cmp(total) < 0 {
 		if txn.Traced {
@@ -940,7 +1004,8 @@ func (p *TxPool) validateTx(txn *types.TxSlot, isLocal bool, stateCache kvcache.
 var maxUint256 = new(uint256.Int).SetAllOne()
 
 // Sender should have enough balance for: gasLimit x feeCap + blobGas x blobFeeCap + transferred_value
-// See YP, Eq (61) in Section 6.2 "Execution"
+// See YP, Eq (61) in Section 6.2 "Execution". RIP-7560: the same is charged from the paymaster, if set
+// (transferred_value is the builder fee)
 func requiredBalance(txn *types.TxSlot) *uint256.Int {
 	// See https://github.com/ethereum/EIPs/pull/3594
 	total := uint256.NewInt(txn.Gas)
@@ -1088,7 +1153,8 @@ func (p *TxPool) ValidateSerializedTxn(serializedTxn []byte) error {
 	return nil
 }
 
-func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (reasons []txpoolcfg.DiscardReason, goodTxs types.TxSlots, err error) {
+// validateTxs - aaReasons are results of validateAATxs, nil if it wasn't called
+func (p *TxPool) validateTxs(txs *types.TxSlots, aaReasons []txpoolcfg.DiscardReason, stateCache kvcache.CacheView) (reasons []txpoolcfg.DiscardReason, goodTxs types.TxSlots, err error) {
 	// reasons is pre-sized for direct indexing, with the default zero
 	// value DiscardReason of NotSet
 	reasons = make([]txpoolcfg.DiscardReason, len(txs.Txs))
@@ -1099,6 +1165,10 @@ func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (
 
 	goodCount := 0
 	for i, txn := range txs.Txs {
+		if aaReasons != nil && aaReasons[i] != txpoolcfg.NotSet {
+			reasons[i] = aaReasons[i]
+			continue
+		}
 		reason := p.validateTx(txn, txs.IsLocal[i], stateCache)
 		if reason == txpoolcfg.Success {
 			goodCount++
@@ -1125,6 +1195,344 @@ func (p *TxPool) validateTxs(txs *types.TxSlots, stateCache kvcache.CacheView) (
 	return reasons, goodTxs, nil
 }
 
+// validateAATxs runs validation frames of local RIP-7560 transactions which are next to execute (nonce of the sender
+// in the latest state). Must be called without p.lock: frames are EVM calls. Transactions with higher nonces are
+// validated when they become next to execute, see validateQueuedAATxs. Remote transactions are validated in
+// batches, see validateRemoteAATxs. Returns nil if there are no such transactions.
+func (p *TxPool) validateAATxs(ctx context.Context, txs *types.TxSlots) ([]txpoolcfg.DiscardReason, error) {
+	if !p.cfg.AccountAbstraction || p.aaValidator == nil {
+		return nil, nil // rejected by validateTx
+	}
+	hasAATxs := false
+	for _, txn := range txs.Txs {
+		if txn.Type == types.AccountAbstractionTxType {
+			hasAATxs = true
+			break
+		}
+	}
+	if !hasAATxs {
+		return nil, nil
+	}
+
+	cacheView, rollback, err := p.latestStateView(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rollback()
+
+	reasons := make([]txpoolcfg.DiscardReason, len(txs.Txs))
+	for i, txn := range txs.Txs {
+		if txn.Type != types.AccountAbstractionTxType {
+			continue
+		}
+		nonce, _, err := accountInfo(cacheView, txs.Senders.AddressAt(i))
+		if err != nil {
+			return nil, err
+		}
+		if txn.Nonce != nonce {
+			continue
+		}
+		if err := p.validateAA(ctx, txn, txn.Rlp); err != nil {
+			reasons[i] = txpoolcfg.AAValidationFailed
+			continue
+		}
+		txn.AAValidated = true
+	}
+	return reasons, nil
+}
+
+// latestStateView - must be called without p.lock
+func (p *TxPool) latestStateView(ctx context.Context) (kvcache.CacheView, func(), error) {
+	coreDB, cache := p.coreDBWithCache()
+	coreTx, err := coreDB.BeginRo(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cacheView, err := cache.View(ctx, coreTx)
+	if err != nil {
+		coreTx.Rollback()
+		return nil, nil, err
+	}
+	return cacheView, coreTx.Rollback, nil
+}
+
+// validateAA runs validation frames of RIP-7560 transaction within the pool's gas and time caps
+func (p *TxPool) validateAA(ctx context.Context, txn *types.TxSlot, txnRlp []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, aaValidationTimeout)
+	defer cancel()
+	if err := p.aaValidator.ValidateAA(ctx, txnRlp, aaValidationGasCap); err != nil {
+		if txn.Traced {
+			p.logger.Info(fmt.Sprintf("TX TRACING: account abstraction validation failed idHash=%x err=%s", txn.IDHash, err))
+		}
+		return err
+	}
+	return nil
+}
+
+// validateAABatch runs validation frames of at most aaValidationBatchSize transactions by aaValidationWorkers
+// goroutines within aaValidationBatchTimeout. Returns errAAValidationSkipped for transactions which didn't fit.
+func (p *TxPool) validateAABatch(ctx context.Context, txns []*types.TxSlot, rlps [][]byte) []error {
+	if len(txns) > aaValidationBatchSize {
+		panic(fmt.Sprintf("account abstraction validation batch is too big: %d", len(txns)))
+	}
+	ctx, cancel := context.WithTimeout(ctx, aaValidationBatchTimeout)
+	defer cancel()
+	errs := make([]error, len(txns))
+	var g errgroup.Group
+	g.SetLimit(aaValidationWorkers)
+	for i := range txns {
+		i := i
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				errs[i] = errAAValidationSkipped
+				return nil
+			}
+			errs[i] = p.validateAA(ctx, txns[i], rlps[i])
+			if errs[i] != nil && ctx.Err() != nil { // interrupted by the batch timeout - not a failure of the transaction
+				errs[i] = errAAValidationSkipped
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return errs
+}
+
+// OnBadPeer sets fn to be called (without p.lock) with peers which sent RIP-7560 transactions failing validation
+func (p *TxPool) OnBadPeer(fn func(peerID types.PeerID)) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.onBadPeer = fn
+}
+
+func aaPeerKey(peerID types.PeerID) [64]byte {
+	if peerID == nil {
+		return [64]byte{}
+	}
+	return gointerfaces.ConvertH512ToHash(peerID)
+}
+
+// queueRemoteAATxLocked - transactions over the queue limits are dropped without a discard reason: they are not
+// known to be invalid and may be announced again
+func (p *TxPool) queueRemoteAATxLocked(rtx aaRemoteTx) {
+	hashS := string(rtx.txn.IDHash[:])
+	if _, ok := p.aaRemoteQueued[hashS]; ok {
+		return
+	}
+	if _, ok := p.unprocessedRemoteByHash[hashS]; ok {
+		return
+	}
+	peer := aaPeerKey(rtx.peerID)
+	if len(p.aaRemoteQueue) >= aaRemoteQueueLimit || p.aaRemoteByPeer[peer] >= aaRemoteQueuePeerLimit {
+		return
+	}
+	p.aaRemoteQueue = append(p.aaRemoteQueue, rtx)
+	p.aaRemoteQueued[hashS] = struct{}{}
+	p.aaRemoteByPeer[peer]++
+}
+
+// validateRemoteAATxs takes a batch of remote RIP-7560 transactions (at most aaValidationPeerBatchLimit of each peer)
+// and runs validation frames of the ones which are next to execute. Passed transactions (and ones with higher nonces,
+// see validateQueuedAATxs) go to processRemoteTxs, failed are discarded and their peers are reported.
+func (p *TxPool) validateRemoteAATxs(ctx context.Context) error {
+	if !p.cfg.AccountAbstraction || p.aaValidator == nil {
+		return nil
+	}
+	p.lock.Lock()
+	if len(p.aaRemoteQueue) == 0 {
+		p.lock.Unlock()
+		return nil
+	}
+	p.lock.Unlock()
+
+	cacheView, rollback, err := p.latestStateView(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback()
+
+	p.lock.Lock()
+	batch := make([]aaRemoteTx, 0, aaValidationBatchSize)
+	batchByPeer := map[[64]byte]int{}
+	rest := p.aaRemoteQueue[:0]
+	for _, rtx := range p.aaRemoteQueue {
+		peer := aaPeerKey(rtx.peerID)
+		if len(batch) == aaValidationBatchSize || batchByPeer[peer] == aaValidationPeerBatchLimit {
+			rest = append(rest, rtx)
+			continue
+		}
+		batch = append(batch, rtx)
+		batchByPeer[peer]++
+		delete(p.aaRemoteQueued, string(rtx.txn.IDHash[:]))
+		if p.aaRemoteByPeer[peer]--; p.aaRemoteByPeer[peer] == 0 {
+			delete(p.aaRemoteByPeer, peer)
+		}
+	}
+	clear(p.aaRemoteQueue[len(rest):])
+	p.aaRemoteQueue = rest
+	p.lock.Unlock()
+
+	var toValidate []int
+	var txns []*types.TxSlot
+	var rlps [][]byte
+	for i, rtx := range batch {
+		nonce, _, err := accountInfo(cacheView, rtx.sender)
+		if err != nil {
+			return err
+		}
+		if rtx.txn.Nonce != nonce {
+			continue
+		}
+		toValidate = append(toValidate, i)
+		txns = append(txns, rtx.txn)
+		rlps = append(rlps, rtx.txn.Rlp)
+	}
+	errs := make([]error, len(batch))
+	for j, err := range p.validateAABatch(ctx, txns, rlps) {
+		errs[toValidate[j]] = err
+		if err == nil {
+			batch[toValidate[j]].txn.AAValidated = true
+		}
+	}
+
+	p.lock.Lock()
+	var badPeers []types.PeerID
+	for i, rtx := range batch {
+		hashS := string(rtx.txn.IDHash[:])
+		switch {
+		case errors.Is(errs[i], errAAValidationSkipped):
+			p.queueRemoteAATxLocked(rtx)
+		case errs[i] != nil:
+			p.discardReasonsLRU.Add(hashS, txpoolcfg.AAValidationFailed)
+			if rtx.peerID != nil {
+				badPeers = append(badPeers, rtx.peerID)
+			}
+		default:
+			if _, ok := p.unprocessedRemoteByHash[hashS]; ok {
+				continue
+			}
+			p.unprocessedRemoteByHash[hashS] = len(p.unprocessedRemoteTxs.Txs)
+			p.unprocessedRemoteTxs.Append(rtx.txn, rtx.sender[:], false)
+		}
+	}
+	onBadPeer := p.onBadPeer
+	p.lock.Unlock()
+
+	if onBadPeer != nil {
+		for _, peerID := range badPeers {
+			onBadPeer(peerID)
+		}
+	}
+	return nil
+}
+
+// validateQueuedAATxs runs validation frames of RIP-7560 transactions which became next to execute after they were
+// added (see onSenderStateChange), without p.lock. A batch at a time: the rest waits for the next call.
+// Passed transactions are promoted, failed are discarded.
+func (p *TxPool) validateQueuedAATxs(ctx context.Context, db kv.RoDB) error {
+	if !p.cfg.AccountAbstraction || p.aaValidator == nil {
+		return nil
+	}
+	p.lock.Lock()
+	if len(p.aaValidationQueue) == 0 {
+		p.lock.Unlock()
+		return nil
+	}
+	p.lock.Unlock()
+
+	cacheView, rollback, err := p.latestStateView(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback()
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	p.lock.Lock()
+	queue := make([]*metaTx, 0, aaValidationBatchSize)
+	txns := make([]*types.TxSlot, 0, aaValidationBatchSize)
+	rlps := make([][]byte, 0, aaValidationBatchSize)
+	for hashS, mt := range p.aaValidationQueue {
+		if len(queue) == aaValidationBatchSize {
+			break
+		}
+		delete(p.aaValidationQueue, hashS)
+		if p.byHash[hashS] != mt { // already removed or replaced
+			continue
+		}
+		rlpTxn, _, _, err := p.getRlpLocked(tx, mt.Tx.IDHash[:])
+		if err != nil {
+			p.lock.Unlock()
+			return err
+		}
+		if rlpTxn == nil {
+			continue
+		}
+		queue = append(queue, mt)
+		txns = append(txns, mt.Tx)
+		rlps = append(rlps, common.Copy(rlpTxn))
+	}
+	p.lock.Unlock()
+	if len(queue) == 0 {
+		return nil
+	}
+
+	errs := p.validateAABatch(ctx, txns, rlps)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	sendersWithChangedState := map[uint64]struct{}{}
+	for i, mt := range queue {
+		hashS := string(mt.Tx.IDHash[:])
+		if p.byHash[hashS] != mt {
+			continue
+		}
+		if errors.Is(errs[i], errAAValidationSkipped) {
+			p.aaValidationQueue[hashS] = mt
+			continue
+		}
+		sendersWithChangedState[mt.Tx.SenderID] = struct{}{}
+		if errs[i] == nil {
+			mt.Tx.AAValidated = true
+			continue
+		}
+		switch mt.currentSubPool {
+		case PendingSubPool:
+			p.pending.Remove(mt, "validateQueuedAATxs", p.logger)
+		case BaseFeeSubPool:
+			p.baseFee.Remove(mt, "validateQueuedAATxs", p.logger)
+		case QueuedSubPool:
+			p.queued.Remove(mt, "validateQueuedAATxs", p.logger)
+		default:
+			//already removed
+		}
+		p.discardLocked(mt, txpoolcfg.AAValidationFailed)
+	}
+	for senderID := range sendersWithChangedState {
+		nonce, balance, err := p.senders.info(cacheView, senderID)
+		if err != nil {
+			return err
+		}
+		p.onSenderStateChange(senderID, nonce, balance, p.blockGasLimit.Load(), cacheView, p.logger)
+	}
+
+	announcements := types.Announcements{}
+	p.promote(p.pendingBaseFee.Load(), p.pendingBlobFee.Load(), &announcements, p.logger)
+	p.pending.EnforceBestInvariants()
+	p.promoted.Reset()
+	p.promoted.AppendOther(announcements)
+	if p.promoted.Len() > 0 {
+		select {
+		case p.newPendingTxs <- p.promoted.Copy():
+		default:
+		}
+	}
+	return nil
+}
+
 // punishSpammer by drop half of it's transactions with high nonce
 func (p *TxPool) punishSpammer(spammer uint64) {
 	count := p.all.count(spammer) / 2
@@ -1181,6 +1589,11 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 		return nil, err
 	}
 
+	aaReasons, err := p.validateAATxs(ctx, &newTransactions)
+	if err != nil {
+		return nil, err
+	}
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -1188,7 +1601,7 @@ func (p *TxPool) AddLocalTxs(ctx context.Context, newTransactions types.TxSlots,
 		return nil, err
 	}
 
-	reasons, newTxs, err := p.validateTxs(&newTransactions, cacheView)
+	reasons, newTxs, err := p.validateTxs(&newTransactions, aaReasons, cacheView)
 	if err != nil {
 		return nil, err
 	}
@@ -1279,7 +1692,7 @@ func (p *TxPool) addTxs(blockNum uint64, cacheView kvcache.CacheView, senders *s
 		if err != nil {
 			return announcements, discardReasons, err
 		}
-		p.onSenderStateChange(senderID, nonce, balance, blockGasLimit, logger)
+		p.onSenderStateChange(senderID, nonce, balance, blockGasLimit, cacheView, logger)
 	}
 
 	p.promote(pendingBaseFee, pendingBlobFee, &announcements, logger)
@@ -1325,10 +1738,11 @@ func (p *TxPool) addTxsOnNewBlock(blockNum uint64, cacheView kvcache.CacheView,
 		for _, change := range changesList.Changes {
 			switch change.Action {
 			case remote.Action_UPSERT, remote.Action_UPSERT_CODE:
-				if change.Incarnation > 0 {
+				addr := gointerfaces.ConvertH160toAddress(change.Address)
+				p.all.addPaymasterSenders(addr, sendersWithChangedState) // RIP-7560 paymasters are contracts
+				if change.Incarnation > 0 && !p.cfg.AccountAbstraction { // RIP-7560 senders are contracts too
 					continue
 				}
-				addr := gointerfaces.ConvertH160toAddress(change.Address)
 				id, ok := senders.getID(addr)
 				if !ok {
 					continue
@@ -1343,7 +1757,7 @@ func (p *TxPool) addTxsOnNewBlock(blockNum uint64, cacheView kvcache.CacheView,
 		if err != nil {
 			return announcements, err
 		}
-		p.onSenderStateChange(senderID, nonce, balance, blockGasLimit, logger)
+		p.onSenderStateChange(senderID, nonce, balance, blockGasLimit, cacheView, logger)
 	}
 
 	return announcements, nil
@@ -1592,11 +2006,12 @@ func (p *TxPool) removeMined(byNonce *BySenderAndNonce, minedTxs []*types.TxSlot
 // which sub pool they will need to go to. Since this depends on other transactions from the same sender by with lower
 // nonces, and also affect other transactions from the same sender with higher nonce, it loops through all transactions
 // for a given senderID
-func (p *TxPool) onSenderStateChange(senderID uint64, senderNonce uint64, senderBalance uint256.Int, blockGasLimit uint64, logger log.Logger) {
+func (p *TxPool) onSenderStateChange(senderID uint64, senderNonce uint64, senderBalance uint256.Int, blockGasLimit uint64, cacheView kvcache.CacheView, logger log.Logger) {
 	noGapsNonce := senderNonce
 	cumulativeRequiredBalance := uint256.NewInt(0)
 	minFeeCap := uint256.NewInt(0).SetAllOne()
 	minTip := uint64(math.MaxUint64)
+	unvalidatedAA := false
 	var toDel []*metaTx // can't delete items while iterate them
 
 	p.all.ascend(senderID, func(mt *metaTx) bool {
@@ -1658,7 +2073,14 @@ func (p *TxPool) onSenderStateChange(senderID uint64, senderNonce uint64, sender
 		// transactions will be able to pay for gas.
 		mt.subPool &^= EnoughBalance
 		mt.cumulativeBalanceDistance = math.MaxUint64
-		if mt.Tx.Nonce >= senderNonce {
+		if mt.Tx.Paymaster != nil {
+			// RIP-7560: paid by the paymaster, doesn't spend sender's balance. The paymaster must cover all transactions
+			// it pays for - running total in order of arrival. Paymaster's state which can't be read doesn't cover any
+			_, paymasterBalance, err := accountInfo(cacheView, *mt.Tx.Paymaster)
+			if err == nil && mt.Tx.Nonce >= senderNonce && !paymasterBalance.Lt(p.all.paymasterCumulativeBalance(mt)) {
+				mt.subPool |= EnoughBalance
+			}
+		} else if mt.Tx.Nonce >= senderNonce {
 			cumulativeRequiredBalance = cumulativeRequiredBalance.Add(cumulativeRequiredBalance, needBalance) // already deleted all transactions with nonce <= sender.nonce
 			if senderBalance.Gt(cumulativeRequiredBalance) || senderBalance.Eq(cumulativeRequiredBalance) {
 				mt.subPool |= EnoughBalance
@@ -1669,6 +2091,19 @@ func (p *TxPool) onSenderStateChange(senderID uint64, senderNonce uint64, sender
 			}
 		}
 
+		// RIP-7560: payment is authorized by validation frames, they run outside of p.lock when the transaction
+		// becomes next to execute (see validateQueuedAATxs). Until then it and following transactions of the sender
+		// can't be pending
+		if mt.Tx.Type == types.AccountAbstractionTxType && !mt.Tx.AAValidated {
+			if mt.Tx.Nonce == senderNonce {
+				p.aaValidationQueue[string(mt.Tx.IDHash[:])] = mt
+			}
+			unvalidatedAA = true
+		}
+		if unvalidatedAA {
+			mt.subPool &^= EnoughBalance
+		}
+
 		mt.subPool &^= NotTooMuchGas
 		if mt.Tx.Gas < blockGasLimit {
 			mt.subPool |= NotTooMuchGas
@@ -1795,6 +2230,9 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 				continue
 			}
 
+			if err := p.validateRemoteAATxs(ctx); err != nil {
+				p.logger.Error("[txpool] validate remote account abstraction txs", "err", err)
+			}
 			if err := p.processRemoteTxs(ctx); err != nil {
 				if grpcutil.IsRetryLater(err) || grpcutil.IsEndOfStream(err) {
 					time.Sleep(3 * time.Second)
@@ -1803,6 +2241,11 @@ func MainLoop(ctx context.Context, db kv.RwDB, p *TxPool, newTxs chan types.Anno
 
 				p.logger.Error("[txpool] process batch remote txs", "err", err)
 			}
+			if db != nil {
+				if err := p.validateQueuedAATxs(ctx, db); err != nil {
+					p.logger.Error("[txpool] validate account abstraction txs", "err", err)
+				}
+			}
 		case <-commitEvery.C:
 			if db != nil && p.Started() {
 				t := time.Now()
@@ -2405,6 +2848,11 @@ func (sc *sendersBatch) info(cacheView kvcache.CacheView, id uint64) (nonce uint
 	if !ok {
 		panic("must not happen")
 	}
+	return accountInfo(cacheView, addr)
+}
+
+// accountInfo - nonce and balance of any account (for example, RIP-7560 paymaster), doesn't need p.lock
+func accountInfo(cacheView kvcache.CacheView, addr common.Address) (nonce uint64, balance uint256.Int, err error) {
 	encoded, err := cacheView.Get(addr.Bytes())
 	if err != nil {
 		return 0, emptySender.balance, err
@@ -2461,6 +2909,49 @@ type BySenderAndNonce struct {
 	search            *metaTx
 	senderIDTxnCount  map[uint64]int    // count of sender's txns in the pool - may differ from nonce
 	senderIDBlobCount map[uint64]uint64 // count of sender's total number of blobs in the pool
+	paymasterTxs      map[common.Address]*sponsoredTxs
+}
+
+// sponsoredTxs - transactions paid by a RIP-7560 paymaster, in order of arrival. The paymaster pays for all of them,
+// so each one is checked against the running total of their required balances (see onSenderStateChange)
+type sponsoredTxs struct {
+	txs        []*metaTx
+	cumulative map[*metaTx]*uint256.Int // nil after txs changed
+}
+
+func (s *sponsoredTxs) add(mt *metaTx) {
+	s.txs = append(s.txs, mt)
+	s.cumulative = nil
+}
+
+func (s *sponsoredTxs) remove(mt *metaTx) {
+	for i, other := range s.txs {
+		if other == mt {
+			s.txs = append(s.txs[:i], s.txs[i+1:]...)
+			s.cumulative = nil
+			return
+		}
+	}
+}
+
+// cumulativeBalance - sum of required balances of mt and transactions of the paymaster which arrived before it
+func (s *sponsoredTxs) cumulativeBalance(mt *metaTx) *uint256.Int {
+	if s.cumulative == nil {
+		s.cumulative = make(map[*metaTx]*uint256.Int, len(s.txs))
+		total := new(uint256.Int)
+		for _, other := range s.txs {
+			next := new(uint256.Int)
+			if _, overflow := next.AddOverflow(total, requiredBalance(other.Tx)); overflow {
+				next = maxUint256
+			}
+			total = next
+			s.cumulative[other] = total
+		}
+	}
+	if total, ok := s.cumulative[mt]; ok {
+		return total
+	}
+	return requiredBalance(mt.Tx)
 }
 
 func (b *BySenderAndNonce) nonce(senderID uint64) (nonce uint64, ok bool) {
@@ -2535,13 +3026,38 @@ func (b *BySenderAndNonce) get(senderID, txNonce uint64) *metaTx {
 	return nil
 }
 
+// paymasterCumulativeBalance - see sponsoredTxs.cumulativeBalance
+func (b *BySenderAndNonce) paymasterCumulativeBalance(mt *metaTx) *uint256.Int {
+	sponsored, ok := b.paymasterTxs[*mt.Tx.Paymaster]
+	if !ok {
+		return requiredBalance(mt.Tx)
+	}
+	return sponsored.cumulativeBalance(mt)
+}
+
+// addPaymasterSenders adds senders of transactions paid by the paymaster to senderIDs
+func (b *BySenderAndNonce) addPaymasterSenders(paymaster common.Address, senderIDs map[uint64]struct{}) {
+	if sponsored, ok := b.paymasterTxs[paymaster]; ok {
+		for _, mt := range sponsored.txs {
+			senderIDs[mt.Tx.SenderID] = struct{}{}
+		}
+	}
+}
+
 // nolint
 func (b *BySenderAndNonce) has(mt *metaTx) bool {
 	return b.tree.Has(mt)
 }
 
 func (b *BySenderAndNonce) delete(mt *metaTx, reason txpoolcfg.DiscardReason, logger log.Logger) {
-	if _, ok := b.tree.Delete(mt); ok {
+	if deleted, ok := b.tree.Delete(mt); ok {
+		if deleted.Tx.Paymaster != nil {
+			if sponsored, ok := b.paymasterTxs[*deleted.Tx.Paymaster]; ok {
+				if sponsored.remove(deleted); len(sponsored.txs) == 0 {
+					delete(b.paymasterTxs, *deleted.Tx.Paymaster)
+				}
+			}
+		}
 		if mt.Tx.Traced {
 			logger.Info("TX TRACING: Deleted tx by nonce", "idHash", fmt.Sprintf("%x", mt.Tx.IDHash), "sender", mt.Tx.SenderID, "nonce", mt.Tx.Nonce, "reason", reason)
 		}
@@ -2584,6 +3100,14 @@ func (b *BySenderAndNonce) replaceOrInsert(mt *metaTx, logger log.Logger) *metaT
 	if mt.Tx.Type == types.BlobTxType && mt.Tx.Blobs != nil {
 		b.senderIDBlobCount[mt.Tx.SenderID] += uint64(len(mt.Tx.Blobs))
 	}
+	if mt.Tx.Paymaster != nil {
+		sponsored, ok := b.paymasterTxs[*mt.Tx.Paymaster]
+		if !ok {
+			sponsored = &sponsoredTxs{}
+			b.paymasterTxs[*mt.Tx.Paymaster] = sponsored
+		}
+		sponsored.add(mt)
+	}
 	return nil
 }
 
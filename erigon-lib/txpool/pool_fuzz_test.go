@@ -318,7 +318,7 @@ func FuzzOnNewBlocks(f *testing.F) {
 
 		cfg := txpoolcfg.DefaultConfig
 		sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-		pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+		pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 		assert.NoError(err)
 
 		err = pool.Start(ctx, db)
@@ -533,7 +533,7 @@ func FuzzOnNewBlocks(f *testing.F) {
 		checkNotify(types.TxSlots{}, txs3, "fork2 mined")
 
 		// add some remote txs from p2p
-		pool.AddRemoteTxs(ctx, p2pReceived)
+		pool.AddRemoteTxs(ctx, p2pReceived, nil)
 		err = pool.processRemoteTxs(ctx)
 		assert.NoError(err)
 		check(p2pReceived, types.TxSlots{}, "p2pmsg1")
@@ -544,7 +544,7 @@ func FuzzOnNewBlocks(f *testing.F) {
 		check(p2pReceived, types.TxSlots{}, "after_flush")
 		checkNotify(p2pReceived, types.TxSlots{}, "after_flush")
 
-		p2, err := New(ch, coreDB, txpoolcfg.DefaultConfig, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+		p2, err := New(ch, coreDB, txpoolcfg.DefaultConfig, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 		assert.NoError(err)
 
 		p2.senders = pool.senders // senders are not persisted
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sync"
 	"testing"
 
 	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
@@ -56,7 +57,7 @@ func TestNonceFromAddress(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -178,7 +179,7 @@ func TestReplaceWithHigherFee(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.NotEqual(nil, pool)
 	ctx := context.Background()
@@ -297,7 +298,7 @@ func TestReverseNonces(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -426,7 +427,7 @@ func TestTxPoke(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -555,7 +556,7 @@ func TestTxPoke(t *testing.T) {
 		}
 		txSlot.IDHash[0] = 1
 		txSlots.Append(txSlot, addr[:], true)
-		pool.AddRemoteTxs(ctx, txSlots)
+		pool.AddRemoteTxs(ctx, txSlots, nil)
 		nonce, ok := pool.NonceFromAddress(addr)
 		assert.True(ok)
 		assert.Equal(uint64(2), nonce)
@@ -577,7 +578,7 @@ func TestTxPoke(t *testing.T) {
 		}
 		txSlot.IDHash[0] = 2
 		txSlots.Append(txSlot, addr[:], true)
-		pool.AddRemoteTxs(ctx, txSlots)
+		pool.AddRemoteTxs(ctx, txSlots, nil)
 		nonce, ok := pool.NonceFromAddress(addr)
 		assert.True(ok)
 		assert.Equal(uint64(2), nonce)
@@ -692,7 +693,7 @@ func TestShanghaiValidateTx(t *testing.T) {
 			}
 
 			cache := &kvcache.DummyCache{}
-			pool, err := New(ch, coreDB, cfg, cache, *u256.N1, shanghaiTime, nil /* agraBlock */, nil /* cancunTime */, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
+			pool, err := New(ch, coreDB, cfg, cache, *u256.N1, shanghaiTime, nil /* agraBlock */, nil /* cancunTime */, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, logger)
 			asrt.NoError(err)
 			ctx := context.Background()
 			tx, err := coreDB.BeginRw(ctx)
@@ -741,7 +742,7 @@ func TestBlobTxReplacement(t *testing.T) {
 
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -960,7 +961,7 @@ func TestDropRemoteAtNoGossip(t *testing.T) {
 	logger := log.New()
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
 
-	txPool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, big.NewInt(0), big.NewInt(0), nil, fixedgas.DefaultMaxBlobsPerBlock, nil, logger)
+	txPool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, big.NewInt(0), big.NewInt(0), nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, logger)
 	assert.NoError(err)
 	require.True(txPool != nil)
 
@@ -1035,7 +1036,7 @@ func TestDropRemoteAtNoGossip(t *testing.T) {
 		txSlot.IDHash[0] = 1
 		txSlots.Append(txSlot, addr[:], true)
 
-		txPool.AddRemoteTxs(ctx, txSlots)
+		txPool.AddRemoteTxs(ctx, txSlots, nil)
 	}
 
 	// empty because AddRemoteTxs logic is intentionally empty
@@ -1068,7 +1069,7 @@ func TestBlobSlots(t *testing.T) {
 	cfg.TotalBlobPoolLimit = 20
 
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, common.Big0, nil, common.Big0, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -1144,7 +1145,7 @@ func TestGasLimitChanged(t *testing.T) {
 	db := memdb.NewTestPoolDB(t)
 	cfg := txpoolcfg.DefaultConfig
 	sendersCache := kvcache.New(kvcache.DefaultCoherentConfig)
-	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, log.New())
+	pool, err := New(ch, coreDB, cfg, sendersCache, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, nil, log.New())
 	assert.NoError(err)
 	require.True(pool != nil)
 	ctx := context.Background()
@@ -1209,3 +1210,194 @@ func TestGasLimitChanged(t *testing.T) {
 
 	assert.Zero(mtx.subPool&NotTooMuchGas, "Should now have block space (again) for the tx")
 }
+
+type testAAValidator struct {
+	lock     sync.Mutex
+	calls    int
+	gasCap   uint64
+	deadline bool
+	fail     map[byte]bool // by the first byte of rlp
+}
+
+func (v *testAAValidator) ValidateAA(ctx context.Context, txnRlp []byte, gasCap uint64) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.calls++
+	v.gasCap = gasCap
+	_, v.deadline = ctx.Deadline()
+	if v.fail[txnRlp[0]] {
+		return fmt.Errorf("not authorized")
+	}
+	return nil
+}
+
+func newAATestPool(t *testing.T, validator AAValidator, senders ...common.Address) (*TxPool, kv.RwDB) {
+	coreDB, _ := temporaltest.NewTestDB(t, datadir.New(t.TempDir()))
+	err := coreDB.Update(context.Background(), func(tx kv.RwTx) error {
+		v := make([]byte, types.EncodeSenderLengthForStorage(1, *uint256.NewInt(common.Ether)))
+		types.EncodeSender(1, *uint256.NewInt(common.Ether), v)
+		for _, sender := range senders {
+			if err := tx.Put(kv.PlainState, sender[:], v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	cfg := txpoolcfg.DefaultConfig
+	cfg.AccountAbstraction = true
+	pool, err := New(make(chan types.Announcements, 100), coreDB, cfg, &kvcache.DummyCache{}, *u256.N1, nil, nil, nil, fixedgas.DefaultMaxBlobsPerBlock, nil, validator, log.New())
+	require.NoError(t, err)
+	return pool, coreDB
+}
+
+func newAATestTxn(nonce uint64, id byte) *types.TxSlot {
+	txn := &types.TxSlot{
+		Type:   types.AccountAbstractionTxType,
+		Nonce:  nonce,
+		FeeCap: *uint256.NewInt(common.GWei),
+		Gas:    100_000,
+		Rlp:    []byte{id},
+	}
+	txn.IDHash[0] = id
+	return txn
+}
+
+func TestAATxValidation(t *testing.T) {
+	require := require.New(t)
+	logger := log.New()
+	ctx := context.Background()
+	sender, paymaster := common.Address{1}, common.Address{2}
+	validator := &testAAValidator{fail: map[byte]bool{3: true}}
+	pool, coreDB := newAATestPool(t, validator, sender)
+
+	var txs types.TxSlots
+	txs.Append(newAATestTxn(1, 1), sender[:], true) // next to execute
+	txs.Append(newAATestTxn(2, 2), sender[:], true) // validated when becomes next to execute
+	txs.Append(newAATestTxn(1, 3), sender[:], true)
+	reasons, err := pool.validateAATxs(ctx, &txs)
+	require.NoError(err)
+	require.Equal([]txpoolcfg.DiscardReason{txpoolcfg.NotSet, txpoolcfg.NotSet, txpoolcfg.AAValidationFailed}, reasons)
+	require.True(txs.Txs[0].AAValidated)
+	require.False(txs.Txs[1].AAValidated)
+	require.Equal(2, validator.calls)
+	require.Equal(uint64(aaValidationGasCap), validator.gasCap)
+	require.True(validator.deadline)
+
+	// paymaster pays instead of the sender
+	txs = types.TxSlots{}
+	txs.Append(newAATestTxn(1, 5), sender[:], false)
+	require.NoError(pool.senders.registerNewSenders(&txs, logger))
+	tx, err := coreDB.BeginRo(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	view, err := (&kvcache.DummyCache{}).View(ctx, tx)
+	require.NoError(err)
+	require.Equal(txpoolcfg.Success, pool.validateTx(txs.Txs[0], false, view))
+	txs.Txs[0].Paymaster = &paymaster
+	require.Equal(txpoolcfg.InsufficientFunds, pool.validateTx(txs.Txs[0], false, view))
+}
+
+func TestRemoteAATxValidation(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	senders := []common.Address{{1}, {2}, {3}}
+	validator := &testAAValidator{fail: map[byte]bool{2: true}}
+	pool, _ := newAATestPool(t, validator, senders...)
+	var badPeers []types.PeerID
+	pool.OnBadPeer(func(peerID types.PeerID) { badPeers = append(badPeers, peerID) })
+	peer1, peer2 := gointerfaces.ConvertHashToH512([64]byte{1}), gointerfaces.ConvertHashToH512([64]byte{2})
+
+	var txs types.TxSlots
+	txs.Append(newAATestTxn(1, 1), senders[0][:], false)
+	txs.Append(newAATestTxn(1, 2), senders[1][:], false) // fails
+	txs.Append(newAATestTxn(1, 3), senders[2][:], false) // over batch limit of the peer
+	txs.Append(newAATestTxn(2, 4), senders[2][:], false) // not next to execute
+	pool.AddRemoteTxs(ctx, txs, peer1)
+	txs = types.TxSlots{}
+	txs.Append(newAATestTxn(2, 5), senders[0][:], false)
+	pool.AddRemoteTxs(ctx, txs, peer2)
+	pool.AddRemoteTxs(ctx, txs, peer2) // duplicate
+	require.Len(pool.aaRemoteQueue, 5)
+	require.Empty(pool.unprocessedRemoteTxs.Txs, "validated before processRemoteTxs")
+
+	require.NoError(pool.validateRemoteAATxs(ctx))
+	require.Equal(2, validator.calls)
+	require.Equal([]types.PeerID{peer1}, badPeers)
+	reason, ok := pool.discardReasonsLRU.Get(string([]byte{2}) + string(make([]byte, 31)))
+	require.True(ok)
+	require.Equal(txpoolcfg.AAValidationFailed, reason)
+	require.Len(pool.unprocessedRemoteTxs.Txs, 2) // 1 - validated, 5 - not next to execute
+	require.True(pool.unprocessedRemoteTxs.Txs[0].AAValidated)
+	require.Len(pool.aaRemoteQueue, 2, "waiting for the next batch")
+	for _, rtx := range pool.aaRemoteQueue {
+		_, discarded := pool.discardReasonsLRU.Get(string(rtx.txn.IDHash[:]))
+		require.False(discarded)
+	}
+
+	require.NoError(pool.validateRemoteAATxs(ctx))
+	require.Equal(3, validator.calls)
+	require.Empty(pool.aaRemoteQueue)
+	require.Len(pool.unprocessedRemoteTxs.Txs, 4)
+	require.Len(badPeers, 1)
+
+	// limit of the peer's queue: dropped, not discarded
+	txs = types.TxSlots{}
+	for i := 0; i < aaRemoteQueuePeerLimit+1; i++ {
+		txs.Append(newAATestTxn(1, byte(100+i)), senders[1][:], false)
+	}
+	pool.AddRemoteTxs(ctx, txs, peer2)
+	require.Len(pool.aaRemoteQueue, aaRemoteQueuePeerLimit)
+	_, discarded := pool.discardReasonsLRU.Get(string(txs.Txs[aaRemoteQueuePeerLimit].IDHash[:]))
+	require.False(discarded)
+}
+
+func TestPaymasterCumulativeBalance(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	senders := []common.Address{{1}, {2}, {3}}
+	paymaster := common.Address{9}
+	pool, coreDB := newAATestPool(t, &testAAValidator{}, append(senders, paymaster)...)
+
+	var txs types.TxSlots
+	for i, sender := range senders {
+		txn := newAATestTxn(1, byte(i+1))
+		txn.Paymaster = &paymaster
+		txn.Gas = 400_000_000 // the paymaster's balance covers 2 of them
+		txn.AAValidated = true
+		txs.Append(txn, sender[:], false)
+	}
+	require.NoError(pool.senders.registerNewSenders(&txs, pool.logger))
+
+	tx, err := coreDB.BeginRo(ctx)
+	require.NoError(err)
+	defer tx.Rollback()
+	view, err := (&kvcache.DummyCache{}).View(ctx, tx)
+	require.NoError(err)
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	_, reasons, err := pool.addTxs(0, view, pool.senders, txs, 0, 0, math.MaxUint64, false, pool.logger)
+	require.NoError(err)
+	require.Equal([]txpoolcfg.DiscardReason{txpoolcfg.NotSet, txpoolcfg.NotSet, txpoolcfg.NotSet}, reasons)
+
+	enoughBalance := func() (res []bool) {
+		for _, txn := range txs.Txs {
+			res = append(res, pool.byHash[string(txn.IDHash[:])].subPool&EnoughBalance != 0)
+		}
+		return res
+	}
+	require.Equal([]bool{true, true, false}, enoughBalance())
+
+	// first one is mined (removed) - the third fits, once the paymaster's state changes
+	pool.discardLocked(pool.byHash[string(txs.Txs[0].IDHash[:])], txpoolcfg.Mined)
+	sendersWithChangedState := map[uint64]struct{}{}
+	pool.all.addPaymasterSenders(paymaster, sendersWithChangedState)
+	require.Len(sendersWithChangedState, 2)
+	for senderID := range sendersWithChangedState {
+		nonce, balance, err := pool.senders.info(view, senderID)
+		require.NoError(err)
+		pool.onSenderStateChange(senderID, nonce, balance, math.MaxUint64, view, pool.logger)
+	}
+	require.True(pool.byHash[string(txs.Txs[2].IDHash[:])].subPool&EnoughBalance != 0)
+}
@@ -57,6 +57,8 @@ type Config struct {
 	MdbxGrowthStep  datasize.ByteSize
 
 	NoGossip bool // this mode doesn't broadcast any txs, and if receive remote-txn - skip it
+
+	AccountAbstraction bool // experimental: accept RIP-7560 transactions
 }
 
 var DefaultConfig = Config{
@@ -114,6 +116,8 @@ const (
 	UnmatchedBlobTxExt  DiscardReason = 29 // KZGcommitments must match the corresponding blobs and proofs
 	BlobTxReplace       DiscardReason = 30 // Cannot replace type-3 blob txn with another type of txn
 	BlobPoolOverflow    DiscardReason = 31 // The total number of blobs (through blob txs) in the pool has reached its limit
+	AAValidationFailed  DiscardReason = 32 // RIP-7560 validation frames failed or didn't authorize the transaction
+	PaymasterStateErr   DiscardReason = 33 // Can't read the state of RIP-7560 paymaster
 
 )
 
@@ -177,6 +181,10 @@ func (r DiscardReason) String() string {
 		return "can't replace blob-txn with a non-blob-txn"
 	case BlobPoolOverflow:
 		return "blobs limit in txpool is full"
+	case AAValidationFailed:
+		return "account abstraction validation failed"
+	case PaymasterStateErr:
+		return "can't read paymaster state"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
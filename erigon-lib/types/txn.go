@@ -108,6 +108,10 @@ type TxSlot struct {
 	Blobs       [][]byte
 	Commitments []gokzg4844.KZGCommitment
 	Proofs      []gokzg4844.KZGProof
+
+	// RIP-7560: Native Account Abstraction
+	Paymaster   *common.Address // Gas is paid by the paymaster, sender's balance is not required
+	AAValidated bool            // Validation frames passed when the transaction became next to execute (set by txpool)
 }
 
 const (
@@ -115,6 +119,8 @@ const (
 	AccessListTxType byte = 1 // EIP-2930
 	DynamicFeeTxType byte = 2 // EIP-1559
 	BlobTxType       byte = 3 // EIP-4844
+	// 4 is reserved for EIP-7702 set code transactions
+	AccountAbstractionTxType byte = 5 // RIP-7560, experimental
 )
 
 var ErrParseTxn = fmt.Errorf("%w transaction", rlp.ErrParse)
@@ -184,7 +190,7 @@ func (ctx *TxParseContext) ParseTransaction(payload []byte, pos int, slot *TxSlo
 	// If it is non-legacy transaction, the transaction type follows, and then the list
 	if !legacy {
 		slot.Type = payload[p]
-		if slot.Type > BlobTxType {
+		if slot.Type > BlobTxType && slot.Type != AccountAbstractionTxType {
 			return 0, fmt.Errorf("%w: unknown transaction type: %d", ErrParseTxn, slot.Type)
 		}
 		p++
@@ -298,6 +304,7 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 	// Compute transaction hash
 	ctx.Keccak1.Reset()
 	ctx.Keccak2.Reset()
+	bodyEnd := len(payload)
 	if !legacy {
 		typeByte := []byte{slot.Type}
 		if _, err = ctx.Keccak1.Write(typeByte); err != nil {
@@ -315,6 +322,7 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 			return 0, fmt.Errorf("%w: computing IdHash (hashing the envelope): %s", ErrParseTxn, err) //nolint
 		}
 		p = dataPos
+		bodyEnd = dataPos + dataLen
 	}
 
 	if ctx.validateRlp != nil {
@@ -322,22 +330,15 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 			return p, err
 		}
 	}
+	if slot.Type == AccountAbstractionTxType {
+		return ctx.parseAATransactionBody(payload, p, bodyEnd, slot, sender, validateHash)
+	}
 
 	// Remember where signing hash data begins (it will need to be wrapped in an RLP list)
 	sigHashPos := p
 	if !legacy {
-		p, err = rlp.U256(payload, p, &ctx.ChainID)
-		if err != nil {
-			return 0, fmt.Errorf("%w: chainId len: %s", ErrParseTxn, err) //nolint
-		}
-		if ctx.ChainID.IsZero() { // zero indicates that the chain ID was not specified in the tx.
-			if ctx.chainIDRequired {
-				return 0, fmt.Errorf("%w: chainID is required", ErrParseTxn)
-			}
-			ctx.ChainID.Set(&ctx.cfg.ChainID)
-		}
-		if !ctx.ChainID.Eq(&ctx.cfg.ChainID) {
-			return 0, fmt.Errorf("%w: %s, %d (expected %d)", ErrParseTxn, "invalid chainID", ctx.ChainID.Uint64(), ctx.cfg.ChainID.Uint64())
+		if p, err = ctx.parseChainID(payload, p); err != nil {
+			return 0, err
 		}
 	}
 	// Next follows the nonce, which we need to parse
@@ -401,46 +402,9 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 
 	// Next follows access list for non-legacy transactions, we are only interesting in number of addresses and storage keys
 	if !legacy {
-		dataPos, dataLen, err = rlp.List(payload, p)
-		if err != nil {
-			return 0, fmt.Errorf("%w: access list len: %s", ErrParseTxn, err) //nolint
+		if p, err = parseAccessList(payload, p, slot); err != nil {
+			return 0, err
 		}
-		tuplePos := dataPos
-		for tuplePos < dataPos+dataLen {
-			var tupleLen int
-			tuplePos, tupleLen, err = rlp.List(payload, tuplePos)
-			if err != nil {
-				return 0, fmt.Errorf("%w: tuple len: %s", ErrParseTxn, err) //nolint
-			}
-			var addrPos int
-			addrPos, err = rlp.StringOfLen(payload, tuplePos, 20)
-			if err != nil {
-				return 0, fmt.Errorf("%w: tuple addr len: %s", ErrParseTxn, err) //nolint
-			}
-			slot.AlAddrCount++
-			var storagePos, storageLen int
-			storagePos, storageLen, err = rlp.List(payload, addrPos+20)
-			if err != nil {
-				return 0, fmt.Errorf("%w: storage key list len: %s", ErrParseTxn, err) //nolint
-			}
-			skeyPos := storagePos
-			for skeyPos < storagePos+storageLen {
-				skeyPos, err = rlp.StringOfLen(payload, skeyPos, 32)
-				if err != nil {
-					return 0, fmt.Errorf("%w: tuple storage key len: %s", ErrParseTxn, err) //nolint
-				}
-				slot.AlStorCount++
-				skeyPos += 32
-			}
-			if skeyPos != storagePos+storageLen {
-				return 0, fmt.Errorf("%w: extraneous space in the tuple after storage key list", ErrParseTxn)
-			}
-			tuplePos += tupleLen
-		}
-		if tuplePos != dataPos+dataLen {
-			return 0, fmt.Errorf("%w: extraneous space in the access list after all tuples", ErrParseTxn)
-		}
-		p = dataPos + dataLen
 	}
 	if slot.Type == BlobTxType {
 		p, err = rlp.U256(payload, p, &slot.BlobFeeCap)
@@ -621,6 +585,178 @@ func (ctx *TxParseContext) parseTransactionBody(payload []byte, pos, p0 int, slo
 	return p, nil
 }
 
+func (ctx *TxParseContext) parseChainID(payload []byte, p int) (int, error) {
+	p, err := rlp.U256(payload, p, &ctx.ChainID)
+	if err != nil {
+		return 0, fmt.Errorf("%w: chainId len: %s", ErrParseTxn, err) //nolint
+	}
+	if ctx.ChainID.IsZero() { // zero indicates that the chain ID was not specified in the tx.
+		if ctx.chainIDRequired {
+			return 0, fmt.Errorf("%w: chainID is required", ErrParseTxn)
+		}
+		ctx.ChainID.Set(&ctx.cfg.ChainID)
+	}
+	if !ctx.ChainID.Eq(&ctx.cfg.ChainID) {
+		return 0, fmt.Errorf("%w: %s, %d (expected %d)", ErrParseTxn, "invalid chainID", ctx.ChainID.Uint64(), ctx.cfg.ChainID.Uint64())
+	}
+	return p, nil
+}
+
+// parseAATransactionBody - RIP-7560 transaction has no signature: sender is a field of the transaction
+// and it's authorized by the account's validation frame (which txpool runs separately, see TxPool.validateAATxs).
+// Gas is the total gas limit of all the frames, builder fee is accounted as Value.
+func (ctx *TxParseContext) parseAATransactionBody(payload []byte, p, bodyEnd int, slot *TxSlot, sender []byte, validateHash func([]byte) error) (int, error) {
+	p, err := ctx.parseChainID(payload, p)
+	if err != nil {
+		return 0, err
+	}
+	p, slot.Nonce, err = rlp.U64(payload, p)
+	if err != nil {
+		return 0, fmt.Errorf("%w: nonce: %s", ErrParseTxn, err) //nolint
+	}
+	dataPos, err := rlp.StringOfLen(payload, p, 20)
+	if err != nil {
+		return 0, fmt.Errorf("%w: sender: %s", ErrParseTxn, err) //nolint
+	}
+	if ctx.withSender {
+		copy(sender, payload[dataPos:dataPos+20])
+	}
+	p = dataPos + 20
+
+	slot.DataLen, slot.DataNonZeroLen = 0, 0
+	parseData := func(name string) error {
+		dataPos, dataLen, err := rlp.String(payload, p)
+		if err != nil {
+			return fmt.Errorf("%w: %s len: %s", ErrParseTxn, name, err) //nolint
+		}
+		slot.DataLen += dataLen
+		for _, byt := range payload[dataPos : dataPos+dataLen] {
+			if byt != 0 {
+				slot.DataNonZeroLen++
+			}
+		}
+		p = dataPos + dataLen
+		return nil
+	}
+	parseOptionalAddress := func(name string) (*common.Address, error) {
+		dataPos, dataLen, err := rlp.String(payload, p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s len: %s", ErrParseTxn, name, err) //nolint
+		}
+		if dataLen != 0 && dataLen != 20 {
+			return nil, fmt.Errorf("%w: unexpected length of %s field: %d", ErrParseTxn, name, dataLen)
+		}
+		p = dataPos + dataLen
+		if dataLen == 0 {
+			return nil, nil
+		}
+		addr := common.BytesToAddress(payload[dataPos : dataPos+dataLen])
+		return &addr, nil
+	}
+
+	if _, err = parseOptionalAddress("deployer"); err != nil {
+		return 0, err
+	}
+	if err = parseData("deployer data"); err != nil {
+		return 0, err
+	}
+	if slot.Paymaster, err = parseOptionalAddress("paymaster"); err != nil {
+		return 0, err
+	}
+	if err = parseData("paymaster data"); err != nil {
+		return 0, err
+	}
+	if err = parseData("execution data"); err != nil {
+		return 0, err
+	}
+	p, err = rlp.U256(payload, p, &slot.Value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: builder fee: %s", ErrParseTxn, err) //nolint
+	}
+	p, err = rlp.U256(payload, p, &slot.Tip)
+	if err != nil {
+		return 0, fmt.Errorf("%w: tip: %s", ErrParseTxn, err) //nolint
+	}
+	p, err = rlp.U256(payload, p, &slot.FeeCap)
+	if err != nil {
+		return 0, fmt.Errorf("%w: feeCap: %s", ErrParseTxn, err) //nolint
+	}
+	slot.Gas = fixedgas.AABaseGas
+	for _, name := range []string{"validation gas", "paymaster validation gas", "post-op gas", "call gas"} {
+		var gas uint64
+		p, gas, err = rlp.U64(payload, p)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s: %s", ErrParseTxn, name, err) //nolint
+		}
+		if slot.Gas+gas < slot.Gas {
+			return 0, fmt.Errorf("%w: total gas limit overflow", ErrParseTxn)
+		}
+		slot.Gas += gas
+	}
+	if p, err = parseAccessList(payload, p, slot); err != nil {
+		return 0, err
+	}
+	if err = parseData("authorization data"); err != nil {
+		return 0, err
+	}
+	if p != bodyEnd {
+		return 0, fmt.Errorf("%w: unexpected leftover after AA tx body", ErrParseTxn)
+	}
+	slot.Creation = false
+
+	_, _ = ctx.Keccak1.(io.Reader).Read(slot.IDHash[:32])
+	if validateHash != nil {
+		if err := validateHash(slot.IDHash[:32]); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// parseAccessList - we are only interesting in number of addresses and storage keys
+func parseAccessList(payload []byte, p int, slot *TxSlot) (int, error) {
+	dataPos, dataLen, err := rlp.List(payload, p)
+	if err != nil {
+		return 0, fmt.Errorf("%w: access list len: %s", ErrParseTxn, err) //nolint
+	}
+	tuplePos := dataPos
+	for tuplePos < dataPos+dataLen {
+		var tupleLen int
+		tuplePos, tupleLen, err = rlp.List(payload, tuplePos)
+		if err != nil {
+			return 0, fmt.Errorf("%w: tuple len: %s", ErrParseTxn, err) //nolint
+		}
+		var addrPos int
+		addrPos, err = rlp.StringOfLen(payload, tuplePos, 20)
+		if err != nil {
+			return 0, fmt.Errorf("%w: tuple addr len: %s", ErrParseTxn, err) //nolint
+		}
+		slot.AlAddrCount++
+		var storagePos, storageLen int
+		storagePos, storageLen, err = rlp.List(payload, addrPos+20)
+		if err != nil {
+			return 0, fmt.Errorf("%w: storage key list len: %s", ErrParseTxn, err) //nolint
+		}
+		skeyPos := storagePos
+		for skeyPos < storagePos+storageLen {
+			skeyPos, err = rlp.StringOfLen(payload, skeyPos, 32)
+			if err != nil {
+				return 0, fmt.Errorf("%w: tuple storage key len: %s", ErrParseTxn, err) //nolint
+			}
+			slot.AlStorCount++
+			skeyPos += 32
+		}
+		if skeyPos != storagePos+storageLen {
+			return 0, fmt.Errorf("%w: extraneous space in the tuple after storage key list", ErrParseTxn)
+		}
+		tuplePos += tupleLen
+	}
+	if tuplePos != dataPos+dataLen {
+		return 0, fmt.Errorf("%w: extraneous space in the access list after all tuples", ErrParseTxn)
+	}
+	return dataPos + dataLen, nil
+}
+
 type PeerID *types.H512
 
 type Hashes []byte // flatten list of 32-byte hashes
@@ -0,0 +1,72 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// aaValidator implements txpool.AAValidator: runs validation frames of RIP-7560 transactions
+// on top of the latest state, state changes are discarded. Execution is aborted when ctx is done
+type aaValidator struct {
+	db          kv.RoDB
+	blockReader services.FullBlockReader
+	engine      consensus.EngineReader
+	chainConfig *chain.Config
+}
+
+func (v *aaValidator) ValidateAA(ctx context.Context, txnRlp []byte, gasCap uint64) error {
+	txn, err := types.DecodeTransaction(txnRlp)
+	if err != nil {
+		return err
+	}
+	aaTxn, ok := txn.(*types.AccountAbstractionTransaction)
+	if !ok {
+		return fmt.Errorf("unexpected transaction type: %d", txn.Type())
+	}
+	if validationGas := aaTxn.ValidationGasLimit + aaTxn.PaymasterValidationGasLimit; validationGas > gasCap {
+		return fmt.Errorf("validation gas %d exceeds txpool cap %d", validationGas, gasCap)
+	}
+
+	tx, err := v.db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	header := rawdb.ReadCurrentHeader(tx)
+	if header == nil {
+		return fmt.Errorf("current header not found")
+	}
+	getHeader := func(hash libcommon.Hash, number uint64) *types.Header {
+		h, _ := v.blockReader.Header(ctx, tx, hash, number)
+		return h
+	}
+	ibs := state.New(rpchelper.NewLatestStateReader(tx))
+	blockContext := core.NewEVMBlockContext(header, core.GetHashFn(header, getHeader), v.engine, nil /* author */)
+	txContext := evmtypes.TxContext{TxHash: aaTxn.Hash(), Origin: aaTxn.SenderAddress, GasPrice: aaTxn.GetFeeCap()}
+	evm := vm.NewEVM(blockContext, txContext, ibs, v.chainConfig, vm.Config{})
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+
+	_, err = core.ValidateAATransaction(evm, aaTxn, new(core.GasPool).AddGas(aaTxn.TotalGasLimit()))
+	if evm.Cancelled() {
+		return fmt.Errorf("validation aborted: %w", ctx.Err())
+	}
+	return err
+}
@@ -188,6 +188,7 @@ type Ethereum struct {
 
 	waitForStageLoopStop chan struct{}
 	waitForMiningStop    chan struct{}
+	waitForRpcStop       chan struct{} // closed when RPC server drained its requests, nil if not started
 
 	txPoolDB                kv.RwDB
 	txPool                  *txpool.TxPool
@@ -198,6 +199,7 @@ type Ethereum struct {
 	notifyMiningAboutNewTxs chan struct{}
 	forkValidator           *engine_helpers.ForkValidator
 	downloader              *downloader.Downloader
+	diskBudget              *disk.Budget // nil if --datadir.max-size is not set
 
 	agg            *libstate.Aggregator
 	blockSnapshots *freezeblocks.RoSnapshots
@@ -354,6 +356,16 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		return nil, err
 	}
 
+	if maxSize := stack.Config().DatadirMaxSize; maxSize > 0 {
+		backend.diskBudget = disk.NewBudget(dirs.DataDir, maxSize.Bytes(), logger)
+		if backend.downloader != nil { // external downloader has own datadir
+			backend.diskBudget.OnLevelChange(func(level disk.BudgetLevel) {
+				backend.downloader.SetPaused(level >= disk.BudgetLow)
+			})
+		}
+		go backend.diskBudget.Run(ctx)
+	}
+
 	kvRPC := remotedbserver.NewKvServer(ctx, backend.chainDB, allSnapshots, allBorSnapshots, agg, logger)
 	backend.notifications.StateChangesConsumer = kvRPC
 	backend.kvRPC = kvRPC
@@ -629,10 +641,15 @@ func New(ctx context.Context, stack *node.Node, config *ethconfig.Config, logger
 		//cacheConfig.MetricsLabel = "txpool"
 		//cacheConfig.StateV3 = config.HistoryV3w
 
+		var aaTxnValidator txpool.AAValidator
+		if config.TxPool.AccountAbstraction {
+			aaTxnValidator = &aaValidator{db: chainKv, blockReader: blockReader, engine: backend.engine, chainConfig: chainConfig}
+		}
+
 		backend.newTxs = make(chan libtypes.Announcements, 1024)
 		//defer close(newTxs)
 		backend.txPoolDB, backend.txPool, backend.txPoolFetch, backend.txPoolSend, backend.txPoolGrpcServer, err = txpooluitl.AllComponents(
-			ctx, config.TxPool, kvcache.NewDummy(), backend.newTxs, chainKv, backend.sentriesClient.Sentries(), stateDiffClient, misc.Eip1559FeeCalculator, logger,
+			ctx, config.TxPool, kvcache.NewDummy(), backend.newTxs, chainKv, backend.sentriesClient.Sentries(), stateDiffClient, misc.Eip1559FeeCalculator, aaTxnValidator, logger,
 		)
 		if err != nil {
 			return nil, err
@@ -1036,7 +1053,12 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 		}
 	}
 
-	s.apiList = jsonrpc.APIList(chainKv, ethRpcClient, txPoolRpcClient, miningRpcClient, ff, stateCache, blockReader, s.agg, &httpRpcCfg, s.engine, s.logger)
+	var indexRepairer *jsonrpc.IndexRepairer
+	if httpRpcCfg.RpcIndexRepair {
+		indexRepairer = jsonrpc.NewIndexRepairer(chainKv, blockReader, s.logger)
+		go indexRepairer.Run(ctx)
+	}
+	s.apiList = jsonrpc.APIList(chainKv, ethRpcClient, txPoolRpcClient, miningRpcClient, ff, stateCache, blockReader, s.agg, &httpRpcCfg, s.engine, indexRepairer, s.diskBudget, s.logger)
 
 	if config.SilkwormRpcDaemon && httpRpcCfg.Enabled {
 		interface_log_settings := silkworm.RpcInterfaceLogSettings{
@@ -1062,7 +1084,9 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config, chainConfig
 		silkwormRPCDaemonService := silkworm.NewRpcDaemonService(s.silkworm, chainKv, settings)
 		s.silkwormRPCDaemonService = &silkwormRPCDaemonService
 	} else {
+		s.waitForRpcStop = make(chan struct{})
 		go func() {
+			defer close(s.waitForRpcStop)
 			if err := cli.StartRpcServer(ctx, &httpRpcCfg, s.apiList, s.logger); err != nil {
 				s.logger.Error("cli.StartRpcServer error", "err", err)
 			}
@@ -1539,7 +1563,7 @@ func (s *Ethereum) Start() error {
 			}
 		}()
 	} else {
-		go stages2.StageLoop(s.sentryCtx, s.chainDB, s.stagedSync, s.sentriesClient.Hd, s.waitForStageLoopStop, s.config.Sync.LoopThrottle, s.logger, s.blockReader, hook)
+		go stages2.StageLoop(s.sentryCtx, s.chainDB, s.stagedSync, s.sentriesClient.Hd, s.waitForStageLoopStop, s.config.Sync.LoopThrottle, s.logger, s.blockReader, hook, s.diskBudget)
 	}
 
 	if s.chainConfig.Bor != nil {
@@ -1594,6 +1618,9 @@ func (s *Ethereum) Stop() error {
 	for _, sentryServer := range s.sentryServers {
 		sentryServer.Close()
 	}
+	if s.waitForRpcStop != nil { // in-flight RPC requests still hold read transactions
+		<-s.waitForRpcStop
+	}
 	if s.txPoolDB != nil {
 		s.txPoolDB.Close()
 	}
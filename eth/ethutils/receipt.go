@@ -80,8 +80,9 @@ func MarshalReceipt(
 			blobGasPrice, err := misc.GetBlobGasPrice(chainConfig, *header.ExcessBlobGas)
 			if err != nil {
 				log.Error(err.Error())
+			} else {
+				fields["blobGasPrice"] = (*hexutil.Big)(blobGasPrice.ToBig())
 			}
-			fields["blobGasPrice"] = blobGasPrice
 			fields["blobGasUsed"] = hexutil.Uint64(misc.GetBlobGasUsed(numBlobs))
 		}
 	}
@@ -51,10 +51,12 @@ type blockFees struct {
 	block       *types.Block // only set if reward percentiles are requested
 	receipts    types.Receipts
 	// filled by processBlock
-	reward               []*big.Int
-	baseFee, nextBaseFee *big.Int
-	gasUsedRatio         float64
-	err                  error
+	reward                       []*big.Int
+	baseFee, nextBaseFee         *big.Int
+	blobBaseFee, nextBlobBaseFee *big.Int
+	gasUsedRatio                 float64
+	blobGasUsedRatio             float64
+	err                          error
 }
 
 // txGasAndReward is sorted in ascending order based on reward
@@ -87,7 +89,20 @@ func (oracle *Oracle) processBlock(bf *blockFees, percentiles []float64) {
 	} else {
 		bf.nextBaseFee = new(big.Int)
 	}
+	// Blob base fee is derived from excess blob gas, it's zero before Cancun (same as base fee before London)
+	bf.blobBaseFee, bf.nextBlobBaseFee = new(big.Int), new(big.Int)
+	if bf.header.ExcessBlobGas != nil {
+		if blobBaseFee, err := misc.GetBlobGasPrice(chainconfig, *bf.header.ExcessBlobGas); err == nil {
+			bf.blobBaseFee = blobBaseFee.ToBig()
+		}
+		if nextBlobBaseFee, err := misc.GetBlobGasPrice(chainconfig, misc.CalcExcessBlobGas(chainconfig, bf.header)); err == nil {
+			bf.nextBlobBaseFee = nextBlobBaseFee.ToBig()
+		}
+	}
 	bf.gasUsedRatio = float64(bf.header.GasUsed) / float64(bf.header.GasLimit)
+	if bf.header.BlobGasUsed != nil {
+		bf.blobGasUsedRatio = float64(*bf.header.BlobGasUsed) / float64(chainconfig.GetMaxBlobGasPerBlock())
+	}
 	if len(percentiles) == 0 {
 		// rewards were not requested, return null
 		return
@@ -192,17 +207,19 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, lastBlock rpc.Block
 // or blocks older than a certain age (specified in maxHistory). The first block of the
 // actually processed range is returned to avoid ambiguity when parts of the requested range
 // are not available or when the head has changed during processing this request.
-// Three arrays are returned based on the processed blocks:
+// Five arrays are returned based on the processed blocks:
 //   - reward: the requested percentiles of effective priority fees per gas of transactions in each
 //     block, sorted in ascending order and weighted by gas used.
 //   - baseFee: base fee per gas in the given block
 //   - gasUsedRatio: gasUsed/gasLimit in the given block
+//   - blobBaseFee: blob base fee per gas in the given block
+//   - blobGasUsedRatio: blobGasUsed/maxBlobGasPerBlock in the given block
 //
-// Note: baseFee includes the next block after the newest of the returned range, because this
-// value can be derived from the newest block.
-func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error) {
+// Note: baseFee and blobBaseFee both include the next block after the newest of the returned range,
+// because this value can be derived from the newest block.
+func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, []*big.Int, []float64, error) {
 	if blocks < 1 {
-		return libcommon.Big0, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
+		return libcommon.Big0, nil, nil, nil, nil, nil, nil // returning with no data and no error means there are no retrievable blocks
 	}
 	if blocks > maxFeeHistory {
 		log.Warn("[GasPriceOracle] Sanitizing fee history length", "requested", blocks, "truncated", maxFeeHistory)
@@ -210,10 +227,10 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	}
 	for i, p := range rewardPercentiles {
 		if p < 0 || p > 100 {
-			return libcommon.Big0, nil, nil, nil, fmt.Errorf("%w: %f", ErrInvalidPercentile, p)
+			return libcommon.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: %f", ErrInvalidPercentile, p)
 		}
 		if i > 0 && p < rewardPercentiles[i-1] {
-			return libcommon.Big0, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", ErrInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
+			return libcommon.Big0, nil, nil, nil, nil, nil, fmt.Errorf("%w: #%d:%f > #%d:%f", ErrInvalidPercentile, i-1, rewardPercentiles[i-1], i, p)
 		}
 	}
 	// Only process blocks if reward percentiles were requested
@@ -228,7 +245,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 	)
 	pendingBlock, pendingReceipts, lastBlock, blocks, err := oracle.resolveBlockRange(ctx, unresolvedLastBlock, blocks, maxHistory)
 	if err != nil || blocks == 0 {
-		return libcommon.Big0, nil, nil, nil, err
+		return libcommon.Big0, nil, nil, nil, nil, nil, err
 	}
 	oldestBlock := lastBlock + 1 - uint64(blocks)
 
@@ -236,14 +253,16 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		next = oldestBlock
 	)
 	var (
-		reward       = make([][]*big.Int, blocks)
-		baseFee      = make([]*big.Int, blocks+1)
-		gasUsedRatio = make([]float64, blocks)
-		firstMissing = blocks
+		reward           = make([][]*big.Int, blocks)
+		baseFee          = make([]*big.Int, blocks+1)
+		gasUsedRatio     = make([]float64, blocks)
+		blobBaseFee      = make([]*big.Int, blocks+1)
+		blobGasUsedRatio = make([]float64, blocks)
+		firstMissing     = blocks
 	)
 	for ; blocks > 0; blocks-- {
 		if err = libcommon.Stopped(ctx.Done()); err != nil {
-			return libcommon.Big0, nil, nil, nil, err
+			return libcommon.Big0, nil, nil, nil, nil, nil, err
 		}
 		// Retrieve the next block number to fetch with this goroutine
 		blockNumber := atomic.AddUint64(&next, 1) - 1
@@ -272,11 +291,12 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		}
 
 		if fees.err != nil {
-			return libcommon.Big0, nil, nil, nil, fees.err
+			return libcommon.Big0, nil, nil, nil, nil, nil, fees.err
 		}
 		i := int(fees.blockNumber - oldestBlock)
 		if fees.header != nil {
 			reward[i], baseFee[i], baseFee[i+1], gasUsedRatio[i] = fees.reward, fees.baseFee, fees.nextBaseFee, fees.gasUsedRatio
+			blobBaseFee[i], blobBaseFee[i+1], blobGasUsedRatio[i] = fees.blobBaseFee, fees.nextBlobBaseFee, fees.blobGasUsedRatio
 		} else {
 			// getting no block and no error means we are requesting into the future (might happen because of a reorg)
 			if i < firstMissing {
@@ -285,7 +305,7 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		}
 	}
 	if firstMissing == 0 {
-		return libcommon.Big0, nil, nil, nil, nil
+		return libcommon.Big0, nil, nil, nil, nil, nil, nil
 	}
 	if len(rewardPercentiles) != 0 {
 		reward = reward[:firstMissing]
@@ -293,5 +313,6 @@ func (oracle *Oracle) FeeHistory(ctx context.Context, blocks int, unresolvedLast
 		reward = nil
 	}
 	baseFee, gasUsedRatio = baseFee[:firstMissing+1], gasUsedRatio[:firstMissing]
-	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, nil
+	blobBaseFee, blobGasUsedRatio = blobBaseFee[:firstMissing+1], blobGasUsedRatio[:firstMissing]
+	return new(big.Int).SetUint64(oldestBlock), reward, baseFee, gasUsedRatio, blobBaseFee, blobGasUsedRatio, nil
 }
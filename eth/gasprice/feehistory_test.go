@@ -62,7 +62,7 @@ func TestFeeHistory(t *testing.T) {
 		cache := jsonrpc.NewGasPriceCache()
 		oracle := gasprice.NewOracle(backend, config, cache)
 
-		first, reward, baseFee, ratio, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
+		first, reward, baseFee, ratio, blobBaseFee, blobRatio, err := oracle.FeeHistory(context.Background(), c.count, c.last, c.percent)
 
 		expReward := c.expCount
 		if len(c.percent) == 0 {
@@ -85,6 +85,12 @@ func TestFeeHistory(t *testing.T) {
 		if len(ratio) != c.expCount {
 			t.Fatalf("Test case %d: gasUsedRatio array length mismatch, want %d, got %d", i, c.expCount, len(ratio))
 		}
+		if len(blobBaseFee) != expBaseFee {
+			t.Fatalf("Test case %d: blobBaseFee array length mismatch, want %d, got %d", i, expBaseFee, len(blobBaseFee))
+		}
+		if len(blobRatio) != c.expCount {
+			t.Fatalf("Test case %d: blobGasUsedRatio array length mismatch, want %d, got %d", i, c.expCount, len(blobRatio))
+		}
 		if err != c.expErr && !errors.Is(err, c.expErr) {
 			t.Fatalf("Test case %d: error mismatch, want %v, got %v", i, c.expErr, err)
 		}
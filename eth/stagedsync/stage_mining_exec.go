@@ -315,6 +315,10 @@ func filterBadTransactions(transactions []types.Transaction, config chain.Config
 		if err != nil {
 			return nil, err
 		}
+		aaTx, isAA := transaction.(*types.AccountAbstractionTransaction)
+		if account == nil && isAA && aaTx.Deployer != nil {
+			account = new(accounts.Account) // sender is going to be deployed by the deployment frame
+		}
 		if account == nil {
 			transactions = transactions[1:]
 			noAccountCnt++
@@ -333,6 +337,20 @@ func filterBadTransactions(transactions []types.Transaction, config chain.Config
 		}
 		missedTxs = 0
 
+		if isAA {
+			// Sender is a smart contract account and gas may be paid by the paymaster,
+			// it's up to the validation frames which run when the transaction is applied
+			newAccount := new(accounts.Account)
+			*newAccount = *account
+			newAccount.Nonce++
+			if err := simStateWriter.UpdateAccountData(sender, account, newAccount); err != nil {
+				return nil, err
+			}
+			filtered = append(filtered, transaction)
+			transactions = transactions[1:]
+			continue
+		}
+
 		// Make sure the sender is an EOA (EIP-3607)
 		if !account.IsEmptyCodeHash() {
 			transactions = transactions[1:]
@@ -162,6 +162,8 @@ type Config struct {
 	MdbxPageSize    datasize.ByteSize
 	MdbxDBSizeLimit datasize.ByteSize
 	MdbxGrowthStep  datasize.ByteSize
+	// DatadirMaxSize - soft limit of the whole datadir size (0 - no limit), see disk.Budget
+	DatadirMaxSize datasize.ByteSize
 	// HealthCheck enables standard grpc health check
 	HealthCheck bool
 
@@ -105,36 +105,36 @@ func TestCheckCompatible(t *testing.T) {
 
 func TestGetBurntContract(t *testing.T) {
 	// Ethereum
-	assert.Nil(t, MainnetChainConfig.GetBurntContract(0))
-	assert.Nil(t, MainnetChainConfig.GetBurntContract(10_000_000))
+	assert.Nil(t, MainnetChainConfig.GetBurntContract(0, 0))
+	assert.Nil(t, MainnetChainConfig.GetBurntContract(10_000_000, 0))
 
 	// Gnosis Chain
-	addr := GnosisChainConfig.GetBurntContract(19_040_000)
+	addr := GnosisChainConfig.GetBurntContract(19_040_000, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x6BBe78ee9e474842Dbd4AB4987b3CeFE88426A92"), *addr)
-	addr = GnosisChainConfig.GetBurntContract(19_040_001)
+	addr = GnosisChainConfig.GetBurntContract(19_040_001, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x6BBe78ee9e474842Dbd4AB4987b3CeFE88426A92"), *addr)
 
 	// Mumbai
-	addr = MumbaiChainConfig.GetBurntContract(22640000)
+	addr = MumbaiChainConfig.GetBurntContract(22640000, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x70bcA57F4579f58670aB2d18Ef16e02C17553C38"), *addr)
-	addr = MumbaiChainConfig.GetBurntContract(22640000 + 1)
+	addr = MumbaiChainConfig.GetBurntContract(22640000+1, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x70bcA57F4579f58670aB2d18Ef16e02C17553C38"), *addr)
-	addr = MumbaiChainConfig.GetBurntContract(41874000 - 1)
+	addr = MumbaiChainConfig.GetBurntContract(41874000-1, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x70bcA57F4579f58670aB2d18Ef16e02C17553C38"), *addr)
-	addr = MumbaiChainConfig.GetBurntContract(41874000)
+	addr = MumbaiChainConfig.GetBurntContract(41874000, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x617b94CCCC2511808A3C9478ebb96f455CF167aA"), *addr)
-	addr = MumbaiChainConfig.GetBurntContract(41874000 + 1)
+	addr = MumbaiChainConfig.GetBurntContract(41874000+1, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x617b94CCCC2511808A3C9478ebb96f455CF167aA"), *addr)
 
 	// Amoy
-	addr = AmoyChainConfig.GetBurntContract(0)
+	addr = AmoyChainConfig.GetBurntContract(0, 0)
 	require.NotNil(t, addr)
 	assert.Equal(t, common.HexToAddress("0x000000000000000000000000000000000000dead"), *addr)
 }
@@ -148,12 +148,11 @@ func Ecrecover(header *types.Header, sigcache *lru.ARCCache[libcommon.Hash, libc
 		return address, nil
 	}
 	// Retrieve the signature from the header extra-data
-	if len(header.Extra) < types.ExtraSealLength {
+	signature := c.GetExtraDataLayout().Seal(header.Extra)
+	if signature == nil {
 		return libcommon.Address{}, errMissingSignature
 	}
 
-	signature := header.Extra[len(header.Extra)-types.ExtraSealLength:]
-
 	// Recover the public key and the Ethereum address
 	pubkey, err := crypto.Ecrecover(SealHash(header, c).Bytes(), signature)
 	if err != nil {
@@ -192,7 +191,7 @@ func encodeSigHeader(w io.Writer, header *types.Header, c *borcfg.BorConfig) {
 		header.GasLimit,
 		header.GasUsed,
 		header.Time,
-		header.Extra[:len(header.Extra)-65], // Yes, this will panic if extra is too short
+		c.GetExtraDataLayout().Unsealed(header.Extra), // nil if extra is too short, such headers have no signature to recover anyway
 		header.MixDigest,
 		header.Nonce,
 	}
@@ -488,7 +487,7 @@ func (c *Bor) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 		return err
 	}
 
-	if err := ValidateHeaderExtraLength(header.Extra); err != nil {
+	if err := ValidateHeaderExtraLength(header.Extra, c.config); err != nil {
 		return err
 	}
 	if err := ValidateHeaderSprintValidators(header, c.config); err != nil {
@@ -506,12 +505,13 @@ func (c *Bor) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 
 // ValidateHeaderExtraLength validates that the extra-data contains both the vanity and signature.
 // header.Extra = header.Vanity + header.ProducerBytes (optional) + header.Seal
-func ValidateHeaderExtraLength(extraBytes []byte) error {
-	if len(extraBytes) < types.ExtraVanityLength {
+func ValidateHeaderExtraLength(extraBytes []byte, config *borcfg.BorConfig) error {
+	layout := config.GetExtraDataLayout()
+	if !layout.HasVanity(extraBytes) {
 		return errMissingVanity
 	}
 
-	if len(extraBytes) < types.ExtraVanityLength+types.ExtraSealLength {
+	if !layout.HasSeal(extraBytes) {
 		return errMissingSignature
 	}
 
@@ -876,11 +876,12 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header, s
 	header.Difficulty = new(big.Int).SetUint64(snap.Difficulty(c.authorizedSigner.Load().signer))
 
 	// Ensure the extra data has all it's components
-	if len(header.Extra) < types.ExtraVanityLength {
-		header.Extra = append(header.Extra, bytes.Repeat([]byte{0x00}, types.ExtraVanityLength-len(header.Extra))...)
+	extraLayout := c.config.GetExtraDataLayout()
+	if !extraLayout.HasVanity(header.Extra) {
+		header.Extra = append(header.Extra, bytes.Repeat([]byte{0x00}, extraLayout.VanityLength-len(header.Extra))...)
 	}
 
-	header.Extra = header.Extra[:types.ExtraVanityLength]
+	header.Extra = extraLayout.Vanity(header.Extra)
 
 	// get validator set if number
 	// Note: headers.Extra has producer set and not validator set. The bor
@@ -937,7 +938,7 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header, s
 	}
 
 	// add extra seal space
-	header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+	header.Extra = append(header.Extra, make([]byte, extraLayout.SealLength)...)
 
 	// Mix digest is reserved for now, set to empty
 	header.MixDigest = libcommon.Hash{}
@@ -1146,7 +1147,7 @@ func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 	if err != nil {
 		return err
 	}
-	copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sighash)
+	copy(c.config.GetExtraDataLayout().Seal(header.Extra), sighash)
 
 	go func() {
 		// Wait until sealing is terminated or delay timeout.
@@ -1638,19 +1639,20 @@ func GetTxDependency(b *types.Block) [][]uint64 {
 }
 
 func GetValidatorBytes(h *types.Header, config *borcfg.BorConfig) []byte {
+	layout := config.GetExtraDataLayout()
 	tempExtra := h.Extra
 
-	if !config.IsNapoli(h.Number.Uint64()) {
-		return tempExtra[types.ExtraVanityLength : len(tempExtra)-types.ExtraSealLength]
-	}
-
-	if len(tempExtra) < types.ExtraVanityLength+types.ExtraSealLength {
+	if !layout.HasSeal(tempExtra) {
 		log.Error("length of extra less is than vanity and seal")
 		return nil
 	}
 
+	if !config.IsNapoli(h.Number.Uint64()) {
+		return layout.Payload(tempExtra)
+	}
+
 	var blockExtraData BlockExtraData
-	if err := rlp.DecodeBytes(tempExtra[types.ExtraVanityLength:len(tempExtra)-types.ExtraSealLength], &blockExtraData); err != nil {
+	if err := rlp.DecodeBytes(layout.Payload(tempExtra), &blockExtraData); err != nil {
 		log.Error("error while decoding block extra data", "err", err)
 		return nil
 	}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strconv"
 
+	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
 )
 
@@ -27,7 +28,15 @@ type BorConfig struct {
 	NapoliBlock                *big.Int          `json:"napoliBlock"`                // Napoli switch block (nil = no fork, 0 = already on Napoli)
 	StateSyncConfirmationDelay map[string]uint64 `json:"stateSyncConfirmationDelay"` // StateSync Confirmation Delay, in seconds, to calculate `to`
 
-	sprints sprints
+	ExtraData *chain.ExtraDataLayout `json:"extraData,omitempty"` // Layout of header extra-data (nil = chain.DefaultExtraDataLayout)
+
+	Forks []*BorFork `json:"forks,omitempty"` // Hardforks declared by config only, in activation order
+
+	// merged with Forks by UnmarshalJSON
+	sprints          sprints
+	producerDelay    map[string]uint64
+	period           map[string]uint64
+	backupMultiplier map[string]uint64
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -35,8 +44,16 @@ func (c *BorConfig) String() string {
 	return "bor"
 }
 
+// GetExtraDataLayout returns how header extra-data is split into vanity, validator bytes and seal
+func (c *BorConfig) GetExtraDataLayout() chain.ExtraDataLayout {
+	if c.ExtraData == nil {
+		return chain.DefaultExtraDataLayout
+	}
+	return *c.ExtraData
+}
+
 func (c *BorConfig) CalculateProducerDelay(number uint64) uint64 {
-	return borKeyValueConfigHelper(c.ProducerDelay, number)
+	return borKeyValueConfigHelper(scheduleOf(c.producerDelay, c.ProducerDelay), number)
 }
 
 func (c *BorConfig) CalculateSprintLength(number uint64) uint64 {
@@ -86,11 +103,11 @@ func (c *BorConfig) CalculateSprintNumber(number uint64) uint64 {
 }
 
 func (c *BorConfig) CalculateBackupMultiplier(number uint64) uint64 {
-	return borKeyValueConfigHelper(c.BackupMultiplier, number)
+	return borKeyValueConfigHelper(scheduleOf(c.backupMultiplier, c.BackupMultiplier), number)
 }
 
 func (c *BorConfig) CalculatePeriod(number uint64) uint64 {
-	return borKeyValueConfigHelper(c.Period, number)
+	return borKeyValueConfigHelper(scheduleOf(c.period, c.Period), number)
 }
 
 // isForked returns whether a fork scheduled at block s is active at the given head block.
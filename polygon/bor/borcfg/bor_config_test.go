@@ -1,9 +1,13 @@
 package borcfg
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
 )
 
 func TestCalculateSprintNumber(t *testing.T) {
@@ -46,3 +50,64 @@ func TestCalculateSprintNumber(t *testing.T) {
 		assert.Equal(t, expectedSprintNumber, cfg.CalculateSprintNumber(blockNumber), blockNumber)
 	}
 }
+
+func TestExtraDataLayout(t *testing.T) {
+	var cfg BorConfig
+	assert.Equal(t, chain.DefaultExtraDataLayout, cfg.GetExtraDataLayout())
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"extraData":{"vanityLength":16,"sealLength":65}}`), &cfg))
+	layout := cfg.GetExtraDataLayout()
+	assert.Equal(t, 16, layout.VanityLength)
+
+	extra := make([]byte, 16+20+65)
+	extra[16] = 1
+	assert.Len(t, layout.Payload(extra), 20)
+	assert.Equal(t, byte(1), layout.Payload(extra)[0])
+	assert.Len(t, layout.Seal(extra), 65)
+	assert.Nil(t, layout.Payload(extra[:70]))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"extraData":{"vanityLength":-1,"sealLength":65}}`), &BorConfig{}))
+	assert.Error(t, json.Unmarshal([]byte(`{"extraData":{"vanityLength":32,"sealLength":1048576}}`), &BorConfig{}))
+}
+
+func TestForks(t *testing.T) {
+	var cfg BorConfig
+	err := json.Unmarshal([]byte(`{
+		"sprint": {"0": 64},
+		"producerDelay": {"0": 6},
+		"period": {"0": 2},
+		"forks": [
+			{"name": "pip1", "block": 256, "sprint": 16, "producerDelay": 4, "period": 1},
+			{"name": "pip2", "time": 1000, "burntContract": "0x0000000000000000000000000000000000000b0b"}
+		]
+	}`), &cfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, uint64(64), cfg.CalculateSprintLength(255))
+	assert.Equal(t, uint64(16), cfg.CalculateSprintLength(256))
+	assert.Equal(t, uint64(4), cfg.CalculateSprintNumber(256))
+	assert.Equal(t, uint64(6), cfg.CalculateProducerDelay(255))
+	assert.Equal(t, uint64(4), cfg.CalculateProducerDelay(256))
+
+	assert.Equal(t, uint64(2), cfg.CalculatePeriod(255))
+	assert.Equal(t, uint64(1), cfg.CalculatePeriod(256))
+
+	assert.Nil(t, cfg.GetBurntContract(300, 999))
+	assert.Equal(t, common.HexToAddress("0xb0b"), *cfg.GetBurntContract(300, 1000))
+	assert.False(t, cfg.IsForkActive("pip2", 300, 999))
+	assert.True(t, cfg.IsForkActive("pip2", 300, 1000))
+	assert.False(t, cfg.IsForkActive("unknown", 300, 1000))
+	assert.Equal(t, []uint64{256}, cfg.GetForkBlocks())
+	assert.Equal(t, []uint64{1000}, cfg.GetForkTimes())
+
+	invalid := []string{
+		`{"forks": [{"block": 1}]}`,
+		`{"forks": [{"name": "a", "block": 1, "time": 1}]}`,
+		`{"forks": [{"name": "a", "time": 1, "sprint": 16}]}`,
+		`{"forks": [{"name": "a", "block": 1}, {"name": "a", "block": 2}]}`,
+		`{"sprint": {"256": 64}, "forks": [{"name": "a", "block": 256, "sprint": 16}]}`,
+	}
+	for _, data := range invalid {
+		assert.Error(t, json.Unmarshal([]byte(data), &BorConfig{}), data)
+	}
+}
@@ -0,0 +1,183 @@
+package borcfg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+)
+
+// BorFork is a Polygon hardfork (PIP) declared entirely in the chain config. It activates either at Block
+// or at the first block with timestamp >= Time. Parameters which are not set keep their previous values;
+// if several active forks set the same parameter, the one listed last wins.
+// Producer count is not a fork parameter: producers of a span are selected by Heimdall.
+type BorFork struct {
+	Name  string   `json:"name"`
+	Block *big.Int `json:"block,omitempty"`
+	Time  *big.Int `json:"time,omitempty"`
+
+	// Sprint based parameters - allowed only in block activated forks, because sprints are counted in blocks
+	Sprint           *uint64 `json:"sprint,omitempty"`
+	ProducerDelay    *uint64 `json:"producerDelay,omitempty"`
+	Period           *uint64 `json:"period,omitempty"`
+	BackupMultiplier *uint64 `json:"backupMultiplier,omitempty"`
+
+	BurntContract *common.Address `json:"burntContract,omitempty"` // Receiver of EIP-1559 base fees, overrides the top-level burntContract
+}
+
+func forkSprint(f *BorFork) *uint64           { return f.Sprint }
+func forkProducerDelay(f *BorFork) *uint64    { return f.ProducerDelay }
+func forkPeriod(f *BorFork) *uint64           { return f.Period }
+func forkBackupMultiplier(f *BorFork) *uint64 { return f.BackupMultiplier }
+
+func (f *BorFork) isActive(number, time uint64) bool {
+	if f.Block != nil {
+		return isForked(f.Block, number)
+	}
+	return isForked(f.Time, time)
+}
+
+func (f *BorFork) validate() error {
+	if f.Name == "" {
+		return errors.New("fork without name")
+	}
+	if (f.Block == nil) == (f.Time == nil) {
+		return fmt.Errorf("fork %s: exactly one of block and time must be set", f.Name)
+	}
+	if f.Time != nil && (f.Sprint != nil || f.ProducerDelay != nil || f.Period != nil || f.BackupMultiplier != nil) {
+		return fmt.Errorf("fork %s: sprint, producerDelay, period and backupMultiplier require block activation", f.Name)
+	}
+	if f.Sprint != nil && *f.Sprint == 0 {
+		return fmt.Errorf("fork %s: zero sprint length", f.Name)
+	}
+	return nil
+}
+
+func (c *BorConfig) UnmarshalJSON(data []byte) error {
+	type borConfig BorConfig // no methods - avoids recursion
+	if err := json.Unmarshal(data, (*borConfig)(c)); err != nil {
+		return err
+	}
+	return c.mergeForks()
+}
+
+// mergeForks validates the forks and merges their block keyed parameters into the schedules used by Calculate* methods
+func (c *BorConfig) mergeForks() error {
+	names := make(map[string]struct{}, len(c.Forks))
+	for _, f := range c.Forks {
+		if err := f.validate(); err != nil {
+			return err
+		}
+		if _, ok := names[f.Name]; ok {
+			return fmt.Errorf("fork %s: declared twice", f.Name)
+		}
+		names[f.Name] = struct{}{}
+	}
+	var sprint map[string]uint64
+	schedules := []struct {
+		name   string
+		field  map[string]uint64
+		param  func(*BorFork) *uint64
+		merged *map[string]uint64
+	}{
+		{"sprint", c.Sprint, forkSprint, &sprint},
+		{"producerDelay", c.ProducerDelay, forkProducerDelay, &c.producerDelay},
+		{"period", c.Period, forkPeriod, &c.period},
+		{"backupMultiplier", c.BackupMultiplier, forkBackupMultiplier, &c.backupMultiplier},
+	}
+	for _, s := range schedules {
+		merged, err := c.withForks(s.field, s.param)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		*s.merged = merged
+	}
+	c.sprints = asSprints(sprint)
+	return nil
+}
+
+// scheduleOf - field merged with forks, or the field itself if the config was not unmarshalled from json
+func scheduleOf(merged, field map[string]uint64) map[string]uint64 {
+	if merged != nil {
+		return merged
+	}
+	return field
+}
+
+// withForks - adds values set by block activated forks to a block keyed config field.
+// Fails if a fork and the field (or two forks) set different values for the same block.
+func (c *BorConfig) withForks(field map[string]uint64, param func(*BorFork) *uint64) (map[string]uint64, error) {
+	var merged map[string]uint64
+	for _, f := range c.Forks {
+		v := param(f)
+		if f.Block == nil || v == nil {
+			continue
+		}
+		if merged == nil {
+			merged = make(map[string]uint64, len(field)+len(c.Forks))
+			for k, v := range field {
+				n, err := strconv.ParseUint(k, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+				merged[strconv.FormatUint(n, 10)] = v // normalize keys, so "016" and "16" collide
+			}
+		}
+		key := f.Block.String()
+		if prev, ok := merged[key]; ok && prev != *v {
+			return nil, fmt.Errorf("fork %s: conflicting value at block %s: %d != %d", f.Name, key, *v, prev)
+		}
+		merged[key] = *v
+	}
+	if merged == nil {
+		return field, nil
+	}
+	return merged, nil
+}
+
+// IsForkActive returns whether the fork declared in `forks` under the given name is active at the block.
+// Engine code of PIPs which can't be expressed by parameters alone should be gated on it.
+func (c *BorConfig) IsForkActive(name string, number, time uint64) bool {
+	for _, f := range c.Forks {
+		if f.Name == name {
+			return f.isActive(number, time)
+		}
+	}
+	return false
+}
+
+// GetForkBlocks returns activation blocks of the declared forks, used by the fork id
+func (c *BorConfig) GetForkBlocks() []uint64 {
+	var blocks []uint64
+	for _, f := range c.Forks {
+		if f.Block != nil {
+			blocks = append(blocks, f.Block.Uint64())
+		}
+	}
+	return blocks
+}
+
+// GetForkTimes returns activation times of the declared forks, used by the fork id
+func (c *BorConfig) GetForkTimes() []uint64 {
+	var times []uint64
+	for _, f := range c.Forks {
+		if f.Time != nil {
+			times = append(times, f.Time.Uint64())
+		}
+	}
+	return times
+}
+
+// GetBurntContract returns the burnt contract set by the active forks, nil - not overridden
+func (c *BorConfig) GetBurntContract(number, time uint64) *common.Address {
+	var addr *common.Address
+	for _, f := range c.Forks {
+		if f.BurntContract != nil && f.isActive(number, time) {
+			addr = f.BurntContract
+		}
+	}
+	return addr
+}
@@ -175,7 +175,7 @@ func (s *Snapshot) Apply(parent *types.Header, headers []*types.Header, logger l
 
 		// change validator set and change proposer
 		if number > 0 && (number+1)%sprintLen == 0 {
-			if err := ValidateHeaderExtraLength(header.Extra); err != nil {
+			if err := ValidateHeaderExtraLength(header.Extra, s.config); err != nil {
 				return snap, err
 			}
 			validatorBytes := GetValidatorBytes(header, s.config)
@@ -40,7 +40,7 @@ func (hv *headerValidator) ValidateHeader(header *types.Header, parent *types.He
 		return err
 	}
 
-	if err := bor.ValidateHeaderExtraLength(header.Extra); err != nil {
+	if err := bor.ValidateHeaderExtraLength(header.Extra, hv.borConfig); err != nil {
 		return err
 	}
 	if err := bor.ValidateHeaderSprintValidators(header, hv.borConfig); err != nil {
@@ -78,6 +78,7 @@ type Client struct {
 	isHTTP          bool
 	services        *serviceRegistry
 	methodAllowList AllowList
+	drain           *drainState // of the server, if the client serves a server-side connection
 
 	idCounter uint32
 
@@ -114,6 +115,7 @@ type clientConn struct {
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.WithValue(context.Background(), clientContextKey{}, c)
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.methodAllowList, 50, false /* traceRequests */, c.logger, 0)
+	handler.drain = c.drain
 	return &clientConn{conn, handler}
 }
 
@@ -198,17 +200,18 @@ func newClient(initctx context.Context, connect reconnectFunc, logger log.Logger
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, logger)
+	c := initClient(conn, randomIDGenerator(), &serviceRegistry{logger: logger}, nil /* drain */, logger)
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, logger log.Logger) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, drain *drainState, logger log.Logger) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
 		idgen:       idgen,
 		isHTTP:      isHTTP,
 		services:    services,
+		drain:       drain,
 		writeConn:   conn,
 		close:       make(chan struct{}),
 		closing:     make(chan struct{}),
@@ -26,6 +26,7 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
 	_ Error = new(CustomError)
+	_ Error = new(shutdownError)
 )
 
 const defaultErrorCode = -32000
@@ -67,6 +68,13 @@ func (e *invalidMessageError) ErrorCode() int { return -32700 }
 
 func (e *invalidMessageError) Error() string { return e.message }
 
+// server is draining connections before shutdown
+type shutdownError struct{}
+
+func (e *shutdownError) ErrorCode() int { return defaultErrorCode }
+
+func (e *shutdownError) Error() string { return "server is shutting down" }
+
 // unable to decode supplied params, or invalid parameters
 type InvalidParamsError struct{ Message string }
 
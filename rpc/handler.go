@@ -64,6 +64,7 @@ type handler struct {
 	conn           jsonWriter                     // where responses will be sent
 	logger         log.Logger
 	allowSubscribe bool
+	drain          *drainState // nil for client-side connections
 
 	allowList     AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
 	forbiddenList ForbiddenList
@@ -327,9 +328,15 @@ func (h *handler) cancelServerSubscriptions(err error) {
 // startCallProc runs fn in a new goroutine and starts tracking it in the h.calls wait group.
 func (h *handler) startCallProc(fn func(*callProc)) {
 	h.callWG.Add(1)
+	if h.drain != nil {
+		h.drain.inflight.Add(1)
+	}
 	go func() {
 		ctx, cancel := context.WithCancel(h.rootCtx)
 		defer h.callWG.Done()
+		if h.drain != nil {
+			defer h.drain.inflight.Add(-1)
+		}
 		defer cancel()
 		fn(&callProc{ctx: ctx})
 	}()
@@ -397,6 +404,12 @@ func (h *handler) handleResponse(msg *jsonrpcMessage) {
 // handleCallMsg executes a call message and returns the answer.
 func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage, stream *jsoniter.Stream) *jsonrpcMessage {
 	start := time.Now()
+	if h.drain.isDraining() {
+		if msg.isCall() {
+			return msg.errorResponse(&shutdownError{})
+		}
+		return nil
+	}
 	switch {
 	case msg.isNotification():
 		h.handleCall(ctx, msg, stream)
@@ -235,6 +235,10 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), code)
 		return
 	}
+	if s.drain.isDraining() {
+		http.Error(w, (&shutdownError{}).Error(), http.StatusServiceUnavailable)
+		return
+	}
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
 	// single request.
@@ -30,6 +30,8 @@ import (
 
 const MetadataApi = "rpc"
 
+const drainPollInterval = 10 * time.Millisecond
+
 // CodecOption specifies which type of messages a codec supports.
 //
 // Deprecated: this option is no longer honored by Server.
@@ -50,6 +52,7 @@ type Server struct {
 	idgen           func() ID
 	run             int32
 	codecs          mapset.Set // mapset.Set[ServerCodec] requires go 1.20
+	drain           drainState
 
 	batchConcurrency    uint
 	disableStreaming    bool
@@ -60,6 +63,17 @@ type Server struct {
 	rpcSlowLogThreshold time.Duration
 }
 
+// drainState is shared by the handlers of a server: once draining started new calls are rejected,
+// in-flight calls are counted so Shutdown can wait for them
+type drainState struct {
+	draining atomic.Bool
+	inflight atomic.Int64
+}
+
+func (d *drainState) isDraining() bool {
+	return d != nil && d.draining.Load()
+}
+
 // NewServer creates a new server instance with no registered handlers.
 func NewServer(batchConcurrency uint, traceRequests, debugSingleRequest, disableStreaming bool, logger log.Logger, rpcSlowLogThreshold time.Duration) *Server {
 	server := &Server{services: serviceRegistry{logger: logger}, idgen: randomIDGenerator(), codecs: mapset.NewSet(), run: 1, batchConcurrency: batchConcurrency,
@@ -97,8 +111,8 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	defer codec.Close()
 
-	// Don't serve if server is stopped.
-	if atomic.LoadInt32(&s.run) == 0 {
+	// Don't serve if server is stopped or shutting down.
+	if atomic.LoadInt32(&s.run) == 0 || s.drain.isDraining() {
 		return
 	}
 
@@ -106,7 +120,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services, s.logger)
+	c := initClient(codec, s.idgen, &s.services, &s.drain, s.logger)
 	<-codec.closed()
 	c.Close()
 }
@@ -122,6 +136,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency, s.traceRequests, s.logger, s.rpcSlowLogThreshold)
 	h.allowSubscribe = false
+	h.drain = &s.drain
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.ReadBatch()
@@ -155,6 +170,36 @@ func (s *Server) Stop() {
 	}
 }
 
+// Shutdown gracefully stops the server: new requests are rejected, in-flight requests are given
+// time to complete until ctx is done, then websocket clients get a close frame with the reason
+// and all codecs are closed - cancelling requests and subscriptions which are still pending.
+func (s *Server) Shutdown(ctx context.Context, reason string) {
+	if !s.drain.draining.CompareAndSwap(false, true) {
+		return
+	}
+	s.logger.Info("RPC server draining", "inflight", s.drain.inflight.Load())
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+drain:
+	for s.drain.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			s.logger.Warn("RPC server drain period is over, cancelling pending requests", "inflight", s.drain.inflight.Load())
+			break drain
+		case <-ticker.C:
+		}
+	}
+
+	s.codecs.Each(func(c interface{}) bool {
+		if wc, ok := c.(*websocketCodec); ok {
+			wc.writeClose(reason)
+		}
+		return true
+	})
+	s.Stop()
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
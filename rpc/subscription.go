@@ -98,6 +98,7 @@ type Notifier struct {
 	buffer       []json.RawMessage
 	callReturned bool
 	activated    bool
+	unsubscribed bool
 }
 
 // CreateSubscription returns a new subscription that is coupled to the
@@ -140,18 +141,41 @@ func (n *Notifier) Notify(id ID, data interface{}) error {
 	return nil
 }
 
+// Unsubscribe ends the subscription from the server side, e.g. after its last notification was sent.
+// The connection forgets the subscription, so later unsubscribe requests of the client fail with
+// ErrSubscriptionNotFound. Notifications sent before still reach the client.
+func (n *Notifier) Unsubscribe(id ID) {
+	n.mu.Lock()
+	if n.sub == nil {
+		panic("can't Unsubscribe before subscription is created")
+	} else if n.sub.ID != id {
+		panic("Unsubscribe with wrong ID")
+	}
+	n.unsubscribed = true
+	registered := n.callReturned
+	n.mu.Unlock()
+
+	// not under n.mu: handler takes its subLock before n.mu in addSubscriptions
+	if registered {
+		n.h.unsubscribe(context.Background(), id) //nolint:errcheck // the client may have unsubscribed already
+	}
+}
+
 // Closed returns a channel that is closed when the RPC connection is closed.
 // Deprecated: use subscription error channel
 func (n *Notifier) Closed() <-chan interface{} {
 	return n.h.conn.closed()
 }
 
-// takeSubscription returns the subscription (if one has been created). No subscription can
-// be created after this call.
+// takeSubscription returns the subscription (if one has been created and not unsubscribed by
+// the server yet). No subscription can be created after this call.
 func (n *Notifier) takeSubscription() *Subscription {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 	n.callReturned = true
+	if n.unsubscribed {
+		return nil
+	}
 	return n.sub
 }
 
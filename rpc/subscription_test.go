@@ -175,6 +175,59 @@ func TestServerUnsubscribe(t *testing.T) {
 	}
 }
 
+func TestNotifierUnsubscribe(t *testing.T) {
+	logger := log.New()
+	p1, p2 := net.Pipe()
+	defer p2.Close()
+
+	server := newTestServer(logger)
+	service := &notificationTestService{unsubscribed: make(chan string, 1)}
+	server.RegisterName("nftest2", service)
+	go server.ServeCodec(NewCodec(p1), 0)
+
+	p2.SetDeadline(time.Now().Add(10 * time.Second))
+	p2.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"nftest2_subscribe","params":["finiteSubscription",3,10]}`))
+
+	var (
+		resps         = make(chan subConfirmation)
+		notifications = make(chan subscriptionResult)
+		errors        = make(chan error, 1)
+	)
+	go waitForMessages(json.NewDecoder(p2), resps, notifications, errors)
+
+	var sub subConfirmation
+	select {
+	case sub = <-resps:
+	case err := <-errors:
+		t.Fatal(err)
+	}
+	// all notifications are delivered, even if the server unsubscribed before the subscription was activated
+	for i := 0; i < 3; i++ {
+		select {
+		case n := <-notifications:
+			if string(n.Result) != fmt.Sprint(10+i) {
+				t.Fatalf("wrong notification %d: %s", i, n.Result)
+			}
+		case err := <-errors:
+			t.Fatal(err)
+		}
+	}
+	if id := <-service.unsubscribed; id != string(sub.subid) {
+		t.Fatalf("wrong subscription ID unsubscribed")
+	}
+
+	// the subscription is gone on the server side
+	p2.Write([]byte(`{"jsonrpc":"2.0","id":2,"method":"nftest2_unsubscribe","params":["` + sub.subid + `"]}`))
+	select {
+	case err := <-errors:
+		if err.Error() != ErrSubscriptionNotFound.Error() {
+			t.Fatalf("wrong error: %v", err)
+		}
+	case resp := <-resps:
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
+
 type subConfirmation struct {
 	reqid int
 	subid ID
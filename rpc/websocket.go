@@ -38,6 +38,7 @@ const (
 	wsPingInterval     = 60 * time.Second
 	wsPingWriteTimeout = 5 * time.Second
 	wsMessageSizeLimit = 32 * 1024 * 1024
+	wsMaxCloseReason   = 123 // control frame payload is limited to 125 bytes, 2 of them take the close code
 )
 
 var wsBufferPool = new(sync.Pool)
@@ -58,6 +59,10 @@ func (s *Server) WebsocketHandler(allowedOrigins []string, jwtSecret []byte, com
 		if jwtSecret != nil && !CheckJwtSecret(w, r, jwtSecret) {
 			return
 		}
+		if s.drain.isDraining() {
+			http.Error(w, (&shutdownError{}).Error(), http.StatusServiceUnavailable)
+			return
+		}
 		conn, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			logger.Warn("WebSocket upgrade failed", "err", err)
@@ -265,6 +270,16 @@ func (wc *websocketCodec) Close() {
 	wc.wg.Wait()
 }
 
+// writeClose sends a close frame telling the peer why the connection is going away.
+// Reason is truncated to fit the control frame.
+func (wc *websocketCodec) writeClose(reason string) {
+	if len(reason) > wsMaxCloseReason {
+		reason = reason[:wsMaxCloseReason]
+	}
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, reason)
+	wc.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsPingWriteTimeout)) //nolint:errcheck
+}
+
 func (wc *websocketCodec) WriteJSON(ctx context.Context, v interface{}) error {
 	err := wc.jsonCodec.WriteJSON(ctx, v)
 	if err == nil {
@@ -115,6 +115,81 @@ func TestWebsocketLargeCall(t *testing.T) {
 	}
 }
 
+// This test checks that Shutdown lets in-flight calls complete, rejects new ones
+// and closes the connection with a close frame carrying the reason.
+func TestWebsocketShutdownDrain(t *testing.T) {
+	t.Parallel()
+	logger := log.New()
+
+	var (
+		srv     = newTestServer(logger)
+		httpsrv = httptest.NewServer(srv.WebsocketHandler([]string{"*"}, nil, false, logger))
+		wsURL   = "ws:" + strings.TrimPrefix(httpsrv.URL, "http:")
+	)
+	defer srv.Stop()
+	defer httpsrv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	if err != nil {
+		t.Fatalf("can't dial: %v", err)
+	}
+	defer conn.Close()
+
+	sleep := (300 * time.Millisecond).Nanoseconds()
+	if err := conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "test_sleep", "params": []int64{sleep}}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor := func(cond func() bool) {
+		for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal("timeout")
+			}
+		}
+	}
+	waitFor(func() bool { return srv.drain.inflight.Load() == 1 })
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx, "restarting")
+	}()
+	waitFor(srv.drain.isDraining)
+
+	if err := conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "test_echo", "params": []interface{}{"x", 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := websocket.DefaultDialer.Dial(wsURL, nil); err == nil {
+		t.Fatal("new connection accepted while draining")
+	}
+
+	var resp map[string]interface{}
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["id"] != float64(2) || resp["error"] == nil {
+		t.Fatalf("expected call rejected while draining, got %v", resp)
+	}
+	resp = nil
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["id"] != float64(1) || resp["error"] != nil {
+		t.Fatalf("expected in-flight call to complete, got %v", resp)
+	}
+
+	_, _, err = conn.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	if !ok {
+		t.Fatalf("expected close frame, got %v", err)
+	}
+	if closeErr.Code != websocket.CloseGoingAway || closeErr.Text != "restarting" {
+		t.Fatalf("wrong close frame: %v", closeErr)
+	}
+	<-shutdownDone
+}
+
 // This test checks that client handles WebSocket ping frames correctly.
 func TestClientWebsocketPing(t *testing.T) {
 	if runtime.GOOS == "windows" {
@@ -519,6 +519,7 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		WebsocketSubscribeLogsChannelSize: ctx.Int(utils.WSSubscribeLogsChannelSize.Name),
 		RpcBatchConcurrency:               ctx.Uint(utils.RpcBatchConcurrencyFlag.Name),
 		RpcStreamingDisable:               ctx.Bool(utils.RpcStreamingDisableFlag.Name),
+		RpcIndexRepair:                    ctx.Bool(utils.RpcIndexRepairFlag.Name),
 		DBReadConcurrency:                 ctx.Int(utils.DBReadConcurrencyFlag.Name),
 		RpcAllowListFilePath:              ctx.String(utils.RpcAccessListFlag.Name),
 		Gascap:                            ctx.Uint64(utils.RpcGasCapFlag.Name),
@@ -535,6 +536,7 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 
 		StateCache:          kvcache.DefaultCoherentConfig,
 		RPCSlowLogThreshold: ctx.Duration(utils.RPCSlowFlag.Name),
+		ShutdownDrain:       ctx.Duration(utils.RpcShutdownDrainFlag.Name),
 	}
 
 	if c.Enabled {
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/c2h5oh/datasize"
+
+	"github.com/ledgerwatch/erigon-lib/common/disk"
 	remote "github.com/ledgerwatch/erigon-lib/gointerfaces/remoteproto"
 	"github.com/ledgerwatch/erigon/p2p"
 
@@ -22,20 +25,37 @@ type AdminAPI interface {
 
 	// AddPeer requests connecting to a remote node.
 	AddPeer(ctx context.Context, url string) (bool, error)
+
+	// DatadirBudget returns the datadir size against --datadir.max-size.
+	DatadirBudget(ctx context.Context) (*DatadirBudget, error)
+
+	// SetDatadirMaxSize changes --datadir.max-size (e.g. "2TB") until restart.
+	SetDatadirMaxSize(ctx context.Context, maxSize string) (*DatadirBudget, error)
 }
 
 // AdminAPIImpl data structure to store things needed for admin_* commands.
 type AdminAPIImpl struct {
 	ethBackend rpchelper.ApiBackend
+	diskBudget *disk.Budget // nil if --datadir.max-size is not set or RPC runs in a separate process
 }
 
 // NewAdminAPI returns AdminAPIImpl instance.
-func NewAdminAPI(eth rpchelper.ApiBackend) *AdminAPIImpl {
+func NewAdminAPI(eth rpchelper.ApiBackend, diskBudget *disk.Budget) *AdminAPIImpl {
 	return &AdminAPIImpl{
 		ethBackend: eth,
+		diskBudget: diskBudget,
 	}
 }
 
+// DatadirBudget - response of admin_datadirBudget
+type DatadirBudget struct {
+	Used    uint64 `json:"used"`
+	MaxSize uint64 `json:"maxSize"`
+	Level   string `json:"level"` // ok, low, exceeded - see disk.BudgetLevel
+}
+
+var errNoDatadirBudget = errors.New("datadir budget is available only in erigon process started with --datadir.max-size")
+
 func (api *AdminAPIImpl) NodeInfo(ctx context.Context) (*p2p.NodeInfo, error) {
 	nodes, err := api.ethBackend.NodeInfo(ctx, 1)
 	if err != nil {
@@ -63,3 +83,27 @@ func (api *AdminAPIImpl) AddPeer(ctx context.Context, url string) (bool, error)
 	}
 	return result.Success, nil
 }
+
+func (api *AdminAPIImpl) DatadirBudget(_ context.Context) (*DatadirBudget, error) {
+	if api.diskBudget == nil {
+		return nil, errNoDatadirBudget
+	}
+	return &DatadirBudget{Used: api.diskBudget.Used(), MaxSize: api.diskBudget.MaxSize(), Level: api.diskBudget.Level().String()}, nil
+}
+
+func (api *AdminAPIImpl) SetDatadirMaxSize(ctx context.Context, maxSize string) (*DatadirBudget, error) {
+	if api.diskBudget == nil {
+		return nil, errNoDatadirBudget
+	}
+	var size datasize.ByteSize
+	if err := size.UnmarshalText([]byte(maxSize)); err != nil {
+		return nil, fmt.Errorf("invalid max size %s: %w", maxSize, err)
+	}
+	if size == 0 {
+		return nil, errors.New("max size must be positive")
+	}
+	if err := api.diskBudget.SetMaxSize(size.Bytes()); err != nil {
+		return nil, err
+	}
+	return api.DatadirBudget(ctx)
+}
@@ -0,0 +1,38 @@
+package jsonrpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common/disk"
+)
+
+func TestAdminDatadirBudget(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewAdminAPI(nil, nil).DatadirBudget(ctx)
+	require.ErrorIs(t, err, errNoDatadirBudget)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), make([]byte, 2000), 0644))
+	budget := disk.NewBudget(dir, 1<<20, log.New())
+	require.NoError(t, budget.Check())
+	api := NewAdminAPI(nil, budget)
+
+	status, err := api.DatadirBudget(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &DatadirBudget{Used: 2000, MaxSize: 1 << 20, Level: "ok"}, status)
+
+	status, err = api.SetDatadirMaxSize(ctx, "1KB")
+	require.NoError(t, err)
+	require.Equal(t, &DatadirBudget{Used: 2000, MaxSize: 1024, Level: "exceeded"}, status)
+
+	_, err = api.SetDatadirMaxSize(ctx, "0")
+	require.Error(t, err)
+	_, err = api.SetDatadirMaxSize(ctx, "lots")
+	require.Error(t, err)
+}
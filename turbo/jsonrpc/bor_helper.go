@@ -23,11 +23,6 @@ const (
 	checkpointInterval = 1024 // Number of blocks after which vote snapshots are saved to db
 )
 
-var (
-	extraVanity = 32 // Fixed number of extra-data prefix bytes reserved for signer vanity
-	extraSeal   = 65 // Fixed number of extra-data suffix bytes reserved for signer seal
-)
-
 var (
 	// errUnknownBlock is returned when the list of signers is requested for a block
 	// that is not part of the local blockchain.
@@ -91,10 +86,10 @@ func getHeaderByHash(ctx context.Context, api *BorImpl, tx kv.Tx, hash common.Ha
 // ecrecover extracts the Ethereum account address from a signed header.
 func ecrecover(header *types.Header, c *borcfg.BorConfig) (common.Address, error) {
 	// Retrieve the signature from the header extra-data
-	if len(header.Extra) < extraSeal {
+	signature := c.GetExtraDataLayout().Seal(header.Extra)
+	if signature == nil {
 		return common.Address{}, errMissingSignature
 	}
-	signature := header.Extra[len(header.Extra)-extraSeal:]
 
 	// Recover the public key and the Ethereum address
 	pubkey, err := crypto.Ecrecover(bor.SealHash(header, c).Bytes(), signature)
@@ -499,10 +499,10 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 
 		// change validator set and change proposer
 		if number > 0 && (number+1)%currentLen == 0 {
-			if err := bor.ValidateHeaderExtraLength(header.Extra); err != nil {
+			if err := bor.ValidateHeaderExtraLength(header.Extra, s.config); err != nil {
 				return nil, err
 			}
-			validatorBytes := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+			validatorBytes := s.config.GetExtraDataLayout().Payload(header.Extra)
 
 			// get validators from headers and use that for new validator set
 			newVals, _ := valset.ParseValidators(validatorBytes)
@@ -2,6 +2,8 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
+
 	"github.com/ledgerwatch/erigon-lib/common/hexutil"
 
 	"github.com/ledgerwatch/erigon-lib/common"
@@ -36,8 +38,14 @@ type ErigonAPI interface {
 	// Gets cannonical block receipt through hash. If the block is not cannonical returns error
 	GetBlockReceiptsByBlockHash(ctx context.Context, cannonicalBlockHash common.Hash) ([]map[string]interface{}, error)
 
+	// Transaction related (see ./erigon_wait_transaction.go)
+	WaitForTransaction(ctx context.Context, txnHash common.Hash, confirmations *hexutil.Uint64, timeout *hexutil.Uint64) (*TransactionInclusion, error)
+
 	// NodeInfo returns a collection of metadata known about the host.
 	NodeInfo(ctx context.Context) ([]p2p.NodeInfo, error)
+
+	// ExplainQuery returns the plan of heavy trace/ots methods (see ./erigon_explain_query.go)
+	ExplainQuery(ctx context.Context, method string, params []json.RawMessage) (*QueryPlan, error)
 }
 
 // ErigonImpl is implementation of the ErigonAPI interface
@@ -0,0 +1,21 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// ExplainQuery implements erigon_explainQuery. Returns the plan (index lookups, snapshot scans, re-execution)
+// which would be used to serve `method` with given `params`, with estimated cost of every step. Nothing is executed.
+// Supported methods: trace_filter, trace_block, ots_searchTransactionsBefore, ots_searchTransactionsAfter
+func (api *ErigonImpl) ExplainQuery(ctx context.Context, method string, params []json.RawMessage) (*QueryPlan, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	return api.explainQuery(tx.(kv.TemporalTx), method, params)
+}
@@ -0,0 +1,87 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/turbo/stages/mock"
+)
+
+func TestExplainQuery(t *testing.T) {
+	m := mock.Mock(t)
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 10, func(i int, gen *core.BlockGen) {
+		gen.SetCoinbase(common.Address{1})
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.InsertChain(chain))
+
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	explain := func(method string, params ...string) (*QueryPlan, error) {
+		raw := make([]json.RawMessage, len(params))
+		for i, p := range params {
+			raw[i] = json.RawMessage(p)
+		}
+		return api.ExplainQuery(context.Background(), method, raw)
+	}
+
+	plan, err := explain("trace_filter", `{"fromBlock":"0x1","toBlock":"0xa"}`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategySnapshotScan, plan.Strategy)
+	require.Empty(t, plan.Rejected)
+
+	plan, err = explain("trace_filter", `{"fromBlock":"0x1","toBlock":"0xa","toAddress":["0x0100000000000000000000000000000000000000"]}`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategyIndexLookup, plan.Strategy)
+	require.Equal(t, QueryStrategyIndexLookup, plan.Steps[0].Strategy)
+	require.Equal(t, uint64(10), plan.Steps[0].EstimatedRows) // block reward of every block
+	require.NotEmpty(t, plan.Rejected)
+
+	plan, err = explain("trace_filter", `{"fromBlock":"0x1","toBlock":"0xa","toAddress":["0x0200000000000000000000000000000000000000"]}`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategyIndexLookup, plan.Strategy)
+	require.Equal(t, uint64(0), plan.Steps[0].EstimatedRows)
+
+	// overlapping indices: the union is bounded by the biggest of them, not by their sum
+	plan, err = explain("trace_filter", `{"fromBlock":"0x1","toBlock":"0xa","toAddress":["0x0100000000000000000000000000000000000000","0x0100000000000000000000000000000000000000"]}`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategyIndexLookup, plan.Strategy)
+	reExec := plan.Steps[len(plan.Steps)-1]
+	require.Equal(t, QueryStrategyReExecution, reExec.Strategy)
+	require.Equal(t, uint64(10), reExec.EstimatedRows)
+
+	plan, err = explain("trace_filter", `{"fromBlock":"0x1","toBlock":"0xa","fromAddress":["0x0100000000000000000000000000000000000000"],"toAddress":["0x0100000000000000000000000000000000000000"],"mode":"intersection"}`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategyIndexLookup, plan.Strategy)
+	require.Equal(t, uint64(0), plan.Steps[len(plan.Steps)-1].EstimatedRows)
+	require.Equal(t, uint64(20), plan.ToTxNum-plan.FromTxNum)
+
+	plan, err = explain("trace_block", `"0x5"`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategyReExecution, plan.Strategy)
+	require.Equal(t, uint64(5), plan.FromBlock)
+
+	plan, err = explain("ots_searchTransactionsBefore", `"0x0100000000000000000000000000000000000000"`, `0`, `25`)
+	require.NoError(t, err)
+	require.Equal(t, QueryStrategyIndexLookup, plan.Strategy)
+
+	_, err = explain("trace_filter")
+	require.Error(t, err)
+	_, err = explain("eth_getBalance", `"0x0100000000000000000000000000000000000000"`)
+	require.Error(t, err)
+}
+
+func TestNeedTraceFilterPlan(t *testing.T) {
+	a, b := common.Address{1}, common.Address{2}
+	single := TraceFilterRequest{ToAddress: []*common.Address{&a}}
+	many := TraceFilterRequest{FromAddress: []*common.Address{&a}, ToAddress: []*common.Address{&b}}
+
+	require.False(t, needTraceFilterPlan(single, 0, 10*queryPlannerMinRangeTxs))
+	require.False(t, needTraceFilterPlan(many, 0, queryPlannerMinRangeTxs-1))
+	require.True(t, needTraceFilterPlan(many, 0, queryPlannerMinRangeTxs))
+}
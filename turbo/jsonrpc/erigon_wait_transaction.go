@@ -0,0 +1,228 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/common/debug"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+const (
+	waitForTransactionDefaultTimeout = time.Minute
+	waitForTransactionMaxTimeout     = 10 * time.Minute
+	// re-check also without new heads: notifications are unavailable in some setups and dropped if the subscriber is slow
+	waitForTransactionPollInterval = 2 * time.Second
+)
+
+// TransactionInclusion - position of a transaction in the canonical chain
+type TransactionInclusion struct {
+	BlockHash        common.Hash    `json:"blockHash"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	Confirmations    hexutil.Uint64 `json:"confirmations"` // 1 - included in the latest block
+}
+
+// WaitForTransaction implements erigon_waitForTransaction. Returns when the transaction is included in a canonical
+// block and has at least `confirmations` (default 1) confirmations, or when `timeout` (in seconds, default 60, max 600,
+// 0 - don't wait) expires - then the current inclusion is returned (nil if not included). Inclusion is re-evaluated on
+// every new head, so a transaction which got reorged out is waited for again. See also eth_subscribe("transactionInclusion").
+func (api *ErigonImpl) WaitForTransaction(ctx context.Context, txnHash common.Hash, confirmations *hexutil.Uint64, timeout *hexutil.Uint64) (*TransactionInclusion, error) {
+	wait := waitForTransactionDefaultTimeout
+	if timeout != nil {
+		wait = min(time.Duration(*timeout)*time.Second, waitForTransactionMaxTimeout)
+	}
+	want := wantConfirmations(confirmations)
+
+	// look up once with the caller's ctx: a short timeout must not hide a transaction which is already included
+	inclusion, err := api.transactionInclusion(ctx, api.db, txnHash)
+	if err != nil {
+		return nil, err
+	}
+	if wait == 0 || (inclusion != nil && uint64(inclusion.Confirmations) >= want) {
+		return inclusion, nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	err = api.watchTransactionInclusion(waitCtx, api.db, txnHash, want, func(i *TransactionInclusion) error {
+		inclusion = i
+		return nil
+	})
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return inclusion, nil
+	}
+	return inclusion, err
+}
+
+// TransactionInclusion implements eth_subscribe("transactionInclusion", txnHash, confirmations) - websocket
+// variant of erigon_waitForTransaction. Notifies with the current inclusion (null if not included) and then every
+// time it changes: new confirmations, reorg. The last notification is the one with `confirmations` (default 1),
+// then the subscription is closed by the server.
+func (api *APIImpl) TransactionInclusion(ctx context.Context, txnHash common.Hash, confirmations *hexutil.Uint64) (*rpc.Subscription, error) {
+	if api.filters == nil {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	want := wantConfirmations(confirmations)
+
+	go func() {
+		defer debug.LogPanic()
+		subCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-rpcSub.Err():
+				cancel()
+			case <-subCtx.Done():
+			}
+		}()
+		err := api.watchTransactionInclusion(subCtx, api.db, txnHash, want, func(inclusion *TransactionInclusion) error {
+			return notifier.Notify(rpcSub.ID, inclusion)
+		})
+		if err == nil {
+			notifier.Unsubscribe(rpcSub.ID)
+			return
+		}
+		if !errors.Is(err, context.Canceled) {
+			log.Warn("[rpc] transaction inclusion subscription", "txnHash", txnHash, "err", err)
+		}
+	}()
+
+	return rpcSub, nil
+}
+
+// wantConfirmations - default is 1: included in the latest block
+func wantConfirmations(confirmations *hexutil.Uint64) uint64 {
+	if confirmations != nil && *confirmations > 0 {
+		return uint64(*confirmations)
+	}
+	return 1
+}
+
+// watchTransactionInclusion calls onChange with the current inclusion of the transaction and then every time it
+// changes, until it has wantConfirmations. Re-evaluated on every new head and, without new heads, every poll interval.
+func (api *BaseAPI) watchTransactionInclusion(ctx context.Context, db kv.RoDB, txnHash common.Hash, wantConfirmations uint64, onChange func(*TransactionInclusion) error) error {
+	ticker := time.NewTicker(waitForTransactionPollInterval)
+	defer ticker.Stop()
+
+	var heads <-chan *types.Header
+	if api.filters != nil {
+		var id rpchelper.HeadsSubID
+		heads, id = api.filters.SubscribeNewHeads(8)
+		defer api.filters.UnsubscribeHeads(id)
+	}
+
+	var last *TransactionInclusion
+	for first := true; ; first = false {
+		inclusion, err := api.transactionInclusion(ctx, db, txnHash)
+		if err != nil {
+			return err
+		}
+		if first || !sameInclusion(inclusion, last) {
+			if err := onChange(inclusion); err != nil {
+				return err
+			}
+			last = inclusion
+		}
+		if inclusion != nil && uint64(inclusion.Confirmations) >= wantConfirmations {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-heads:
+			if !ok {
+				heads = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func sameInclusion(a, b *TransactionInclusion) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// transactionInclusion - nil if the transaction is not in the canonical chain (yet, or anymore after reorg)
+func (api *BaseAPI) transactionInclusion(ctx context.Context, db kv.RoDB, txnHash common.Hash) (*TransactionInclusion, error) {
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNum, ok, err := api.txnLookup(ctx, tx, txnHash)
+	if err != nil {
+		return nil, err
+	}
+	cc, err := api.chainConfig(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	isBorStateSyncTxn := false
+	if !ok && cc.Bor != nil {
+		blockNum, ok, err = api._blockReader.EventLookup(ctx, tx, txnHash)
+		if err != nil {
+			return nil, err
+		}
+		isBorStateSyncTxn = ok
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, nil
+	}
+	txnIndex := -1
+	if isBorStateSyncTxn {
+		txnIndex = block.Transactions().Len() // state sync txn follows regular ones
+	} else {
+		for i, txn := range block.Transactions() {
+			if txn.Hash() == txnHash {
+				txnIndex = i
+				break
+			}
+		}
+	}
+	if txnIndex < 0 { // lookup entry is stale: points to a block which is not canonical anymore
+		return nil, nil
+	}
+
+	head, err := rpchelper.GetLatestBlockNumber(tx)
+	if err != nil {
+		return nil, err
+	}
+	if head < blockNum {
+		return nil, nil
+	}
+	return &TransactionInclusion{
+		BlockHash:        block.Hash(),
+		BlockNumber:      hexutil.Uint64(blockNum),
+		TransactionIndex: hexutil.Uint64(txnIndex),
+		Confirmations:    hexutil.Uint64(head - blockNum + 1),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+)
+
+func TestWaitForTransaction(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewErigonAPI(newBaseApiForTest(m), m.DB, nil)
+	ctx := context.Background()
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	head, err := rpchelper.GetLatestBlockNumber(tx)
+	require.NoError(t, err)
+	block, err := m.BlockReader.BlockByNumber(ctx, tx, 10)
+	require.NoError(t, err)
+	require.NotZero(t, block.Transactions().Len())
+	txn := block.Transactions()[0]
+	tx.Rollback()
+
+	// already confirmed - returns immediately
+	inclusion, err := api.WaitForTransaction(ctx, txn.Hash(), nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, &TransactionInclusion{
+		BlockHash:     block.Hash(),
+		BlockNumber:   10,
+		Confirmations: hexutil.Uint64(head - 10 + 1),
+	}, inclusion)
+
+	// zero timeout - no wait, but an included transaction is still found
+	zero := hexutil.Uint64(0)
+	inclusion, err = api.WaitForTransaction(ctx, txn.Hash(), nil, &zero)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint64(10), inclusion.BlockNumber)
+	confirmations := hexutil.Uint64(head - 10 + 2)
+	inclusion, err = api.WaitForTransaction(ctx, txn.Hash(), &confirmations, &zero)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint64(head-10+1), inclusion.Confirmations)
+
+	// not enough confirmations until timeout - returns current inclusion
+	timeout := hexutil.Uint64(1)
+	inclusion, err = api.WaitForTransaction(ctx, txn.Hash(), &confirmations, &timeout)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint64(head-10+1), inclusion.Confirmations)
+
+	// unknown transaction
+	inclusion, err = api.WaitForTransaction(ctx, libcommon.Hash{1}, nil, &timeout)
+	require.NoError(t, err)
+	require.Nil(t, inclusion)
+
+	// cancelled by caller
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = api.WaitForTransaction(cancelled, libcommon.Hash{1}, nil, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestTransactionInclusionSubscription(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx := context.Background()
+	ff := rpchelper.New(ctx, nil, nil, nil, func() {}, m.Log)
+	base := NewBaseApi(ff, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	srv := rpc.NewServer(50, false, false, true, m.Log, 0)
+	require.NoError(t, srv.RegisterName("eth", NewEthAPI(base, m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, m.Log)))
+	t.Cleanup(srv.Stop)
+	client := rpc.DialInProc(srv, m.Log)
+	t.Cleanup(client.Close)
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	head, err := rpchelper.GetLatestBlockNumber(tx)
+	require.NoError(t, err)
+	block, err := m.BlockReader.BlockByNumber(ctx, tx, 10)
+	require.NoError(t, err)
+	txn := block.Transactions()[0]
+	tx.Rollback()
+
+	receive := func(ch chan *TransactionInclusion) *TransactionInclusion {
+		select {
+		case inclusion := <-ch:
+			return inclusion
+		case <-time.After(10 * time.Second):
+			t.Fatal("no notification")
+			return nil
+		}
+	}
+
+	ch := make(chan *TransactionInclusion, 1)
+	sub, err := client.Subscribe(ctx, "eth", ch, "transactionInclusion", txn.Hash())
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	require.Equal(t, &TransactionInclusion{
+		BlockHash:     block.Hash(),
+		BlockNumber:   10,
+		Confirmations: hexutil.Uint64(head - 10 + 1),
+	}, receive(ch))
+
+	// unknown transaction - notified that it's not included yet
+	unknown := make(chan *TransactionInclusion, 1)
+	unknownSub, err := client.Subscribe(ctx, "eth", unknown, "transactionInclusion", libcommon.Hash{1}, hexutil.Uint64(2))
+	require.NoError(t, err)
+	defer unknownSub.Unsubscribe()
+	require.Nil(t, receive(unknown))
+}
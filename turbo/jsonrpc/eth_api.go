@@ -125,6 +125,8 @@ type BaseAPI struct {
 
 	evmCallTimeout time.Duration
 	dirs           datadir.Dirs
+
+	indexRepairer *IndexRepairer // nil if RPC has no write access to the db
 }
 
 func NewBaseApi(f *rpchelper.Filters, stateCache kvcache.Cache, blockReader services.FullBlockReader, agg *libstate.Aggregator, singleNodeMode bool, evmCallTimeout time.Duration, engine consensus.EngineReader, dirs datadir.Dirs) *BaseAPI {
@@ -176,7 +178,11 @@ func (api *BaseAPI) genesis(ctx context.Context, tx kv.Tx) (*types.Block, error)
 }
 
 func (api *BaseAPI) txnLookup(ctx context.Context, tx kv.Tx, txnHash common.Hash) (uint64, bool, error) {
-	return api._txnReader.TxnLookup(ctx, tx, txnHash)
+	blockNum, ok, err := api._txnReader.TxnLookup(ctx, tx, txnHash)
+	if err == nil && !ok {
+		api.indexRepairer.TxLookupMiss()
+	}
+	return blockNum, ok, err
 }
 
 func (api *BaseAPI) blockByNumberWithSenders(ctx context.Context, tx kv.Tx, number uint64) (*types.Block, error) {
@@ -207,13 +213,16 @@ func (api *BaseAPI) blockWithSenders(ctx context.Context, tx kv.Tx, hash common.
 			return it, nil
 		}
 	}
-	block, _, err := api._blockReader.BlockWithSenders(ctx, tx, hash, number)
+	block, senders, err := api._blockReader.BlockWithSenders(ctx, tx, hash, number)
 	if err != nil {
 		return nil, err
 	}
 	if block == nil { // don't save nil's to cache
 		return nil, nil
 	}
+	if err := api.indexRepairer.checkSenders(tx, block, senders); err != nil {
+		log.Warn("[rpc] can't check senders of block", "number", number, "err", err)
+	}
 	// don't save empty blocks to cache, because in Erigon
 	// if block become non-canonical - we remove it's transactions, but block can become canonical in future
 	if block.Transactions().Len() == 0 {
@@ -451,6 +460,13 @@ func NewRPCTransaction(tx types.Transaction, blockHash common.Hash, blockNumber
 		result.GasPrice = computeGasPrice(tx, blockHash, baseFee)
 		result.MaxFeePerBlobGas = (*hexutil.Big)(t.MaxFeePerBlobGas.ToBig())
 		result.BlobVersionedHashes = t.BlobVersionedHashes
+	case *types.AccountAbstractionTransaction:
+		chainId.Set(t.ChainID)
+		result.ChainID = (*hexutil.Big)(chainId.ToBig())
+		result.Tip = (*hexutil.Big)(t.Tip.ToBig())
+		result.FeeCap = (*hexutil.Big)(t.FeeCap.ToBig())
+		result.Accesses = &t.AccessList
+		result.GasPrice = computeGasPrice(tx, blockHash, baseFee)
 	}
 	signer := types.LatestSignerForChainID(chainId.ToBig())
 	result.From, _ = tx.Sender(*signer)
@@ -0,0 +1,347 @@
+package jsonrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/metrics"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// DerivedIndex is an index which can be re-generated from canonical block bodies
+type DerivedIndex string
+
+const (
+	DerivedIndexSenders  DerivedIndex = "senders"
+	DerivedIndexTxLookup DerivedIndex = "txLookup"
+	// Receipts are not listed: they are not persisted (except genesis) and are re-computed by RPC on every request
+)
+
+// indexRepairSegmentSize - amount of blocks re-generated by one job, same as the smallest snapshot segment
+const indexRepairSegmentSize = snaptype.Erigon2MinSegmentSize
+
+const indexRepairQueueSize = 64
+
+// indexRepairScanLimit - max amount of blocks checked by one txLookup scan job
+const indexRepairScanLimit = 100_000
+
+var (
+	indexRepairQueueLen = metrics.GetOrCreateGauge(`rpc_index_repair_queue`)
+
+	indexRepairEnqueued = map[DerivedIndex]metrics.Counter{
+		DerivedIndexSenders:  metrics.GetOrCreateCounter(`rpc_index_repair_jobs{index="senders",result="enqueued"}`),
+		DerivedIndexTxLookup: metrics.GetOrCreateCounter(`rpc_index_repair_jobs{index="txLookup",result="enqueued"}`),
+	}
+	indexRepairDropped = map[DerivedIndex]metrics.Counter{
+		DerivedIndexSenders:  metrics.GetOrCreateCounter(`rpc_index_repair_jobs{index="senders",result="dropped"}`),
+		DerivedIndexTxLookup: metrics.GetOrCreateCounter(`rpc_index_repair_jobs{index="txLookup",result="dropped"}`),
+	}
+	indexRepairFailed = map[DerivedIndex]metrics.Counter{
+		DerivedIndexSenders:  metrics.GetOrCreateCounter(`rpc_index_repair_jobs{index="senders",result="failed"}`),
+		DerivedIndexTxLookup: metrics.GetOrCreateCounter(`rpc_index_repair_jobs{index="txLookup",result="failed"}`),
+	}
+	indexRepairBlocks = map[DerivedIndex]metrics.Counter{
+		DerivedIndexSenders:  metrics.GetOrCreateCounter(`rpc_index_repair_blocks{index="senders"}`),
+		DerivedIndexTxLookup: metrics.GetOrCreateCounter(`rpc_index_repair_blocks{index="txLookup"}`),
+	}
+)
+
+type indexRepairJob struct {
+	index DerivedIndex
+	from  uint64 // first block of the segment
+	scan  bool   // find segments with missing entries first, see scanTxLookup
+}
+
+// IndexRepairer - re-generates senders and txLookup entries of canonical blocks which RPC found missing.
+// RPC only detects and enqueues (cheap, non-blocking), the work is done by Run in background - one
+// segment of indexRepairSegmentSize blocks per job. Needs write access to the db, so it's available
+// only if RPC is served by the Erigon process itself.
+type IndexRepairer struct {
+	db          kv.RwDB
+	blockReader services.FullBlockReader
+	logger      log.Logger
+
+	queue   chan indexRepairJob
+	lock    sync.Mutex
+	pending map[indexRepairJob]struct{}
+
+	txLookupVerified uint64 // blocks below are already checked by scanTxLookup, accessed only by Run
+}
+
+func NewIndexRepairer(db kv.RwDB, blockReader services.FullBlockReader, logger log.Logger) *IndexRepairer {
+	return &IndexRepairer{
+		db:          db,
+		blockReader: blockReader,
+		logger:      logger,
+		queue:       make(chan indexRepairJob, indexRepairQueueSize),
+		pending:     map[indexRepairJob]struct{}{},
+	}
+}
+
+// Enqueue schedules re-generation of `index` for the segment containing blockNum. Never blocks:
+// if the queue is full, the job is dropped - RPC will detect the gap again on the next read.
+func (r *IndexRepairer) Enqueue(index DerivedIndex, blockNum uint64) bool {
+	if r == nil {
+		return false
+	}
+	return r.enqueue(indexRepairJob{index: index, from: blockNum - blockNum%indexRepairSegmentSize})
+}
+
+func (r *IndexRepairer) enqueue(job indexRepairJob) bool {
+	index := job.index
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.pending[job]; ok {
+		return false
+	}
+	select {
+	case r.queue <- job:
+		r.pending[job] = struct{}{}
+		indexRepairEnqueued[index].Inc()
+		indexRepairQueueLen.SetInt(len(r.queue))
+		return true
+	default:
+		indexRepairDropped[index].Inc()
+		return false
+	}
+}
+
+// Run processes enqueued jobs until ctx is cancelled
+func (r *IndexRepairer) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-r.queue:
+			indexRepairQueueLen.SetInt(len(r.queue))
+			repaired, err := r.process(ctx, job)
+			r.lock.Lock()
+			delete(r.pending, job)
+			r.lock.Unlock()
+			if err != nil {
+				indexRepairFailed[job.index].Inc()
+				r.logger.Warn("[rpc] index repair failed", "index", job.index, "segment", job.from, "err", err)
+				continue
+			}
+			indexRepairBlocks[job.index].AddInt(repaired)
+			if repaired > 0 {
+				r.logger.Info("[rpc] repaired missing index entries", "index", job.index, "segment", job.from, "blocks", repaired)
+			}
+		}
+	}
+}
+
+// checkSenders - called by RPC for every block it reads: enqueues repair if senders of a canonical block
+// are missing. Does nothing (and reads nothing) if all senders are present.
+func (r *IndexRepairer) checkSenders(tx kv.Tx, block *types.Block, senders []common.Address) error {
+	if r == nil || len(senders) == block.Transactions().Len() {
+		return nil
+	}
+	number := block.NumberU64()
+	if number <= r.blockReader.FrozenBlocks() { // frozen blocks keep senders in snapshots
+		return nil
+	}
+	if canonical, err := rawdb.ReadCanonicalHash(tx, number); err != nil || canonical != block.Hash() {
+		return err
+	}
+	progress, err := stages.GetStageProgress(tx, stages.Senders)
+	if err != nil {
+		return err
+	}
+	if number <= progress {
+		r.Enqueue(DerivedIndexSenders, number)
+	}
+	return nil
+}
+
+// TxLookupMiss - called by RPC when txLookup has no entry for a transaction hash. The block of the transaction
+// is unknown (and most of misses are txs which are not mined yet), so only a background scan of blocks not
+// checked before is scheduled - repeated misses are cheap.
+func (r *IndexRepairer) TxLookupMiss() {
+	if r == nil {
+		return
+	}
+	r.enqueue(indexRepairJob{index: DerivedIndexTxLookup, scan: true})
+}
+
+func (r *IndexRepairer) process(ctx context.Context, job indexRepairJob) (int, error) {
+	if job.scan {
+		return r.scanTxLookup(ctx)
+	}
+	return r.repair(ctx, job)
+}
+
+// scanTxLookup - checks txLookup entries of not frozen blocks (up to indexRepairScanLimit of them), starting
+// from where the previous scan stopped, and repairs segments with missing entries
+func (r *IndexRepairer) scanTxLookup(ctx context.Context) (repaired int, err error) {
+	var segments []uint64
+	var verified uint64
+	if err := r.db.View(ctx, func(tx kv.Tx) error {
+		progress, err := stages.GetStageProgress(tx, stages.TxLookup)
+		if err != nil {
+			return err
+		}
+		pruned, err := stages.GetStagePruneProgress(tx, stages.TxLookup)
+		if err != nil {
+			return err
+		}
+		from := max(r.txLookupVerified, r.blockReader.FrozenBlocks()+1, pruned)
+		to := min(progress+1, from+indexRepairScanLimit)
+		verified = max(r.txLookupVerified, to)
+		for number := from; number < to; number++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			hash, err := rawdb.ReadCanonicalHash(tx, number)
+			if err != nil {
+				return err
+			}
+			if hash == (common.Hash{}) {
+				continue
+			}
+			block, _, err := r.blockReader.BlockWithSenders(ctx, tx, hash, number)
+			if err != nil {
+				return err
+			}
+			if block == nil || block.Transactions().Len() == 0 {
+				continue
+			}
+			txs := block.Transactions()
+			n, err := rawdb.ReadTxLookupEntry(tx, txs[len(txs)-1].Hash())
+			if err != nil {
+				return err
+			}
+			if n == nil {
+				segment := number - number%indexRepairSegmentSize
+				segments = append(segments, segment)
+				number = segment + indexRepairSegmentSize - 1 // the whole segment is repaired at once
+			}
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	for _, segment := range segments {
+		n, err := r.repair(ctx, indexRepairJob{index: DerivedIndexTxLookup, from: segment})
+		if err != nil {
+			return repaired, err
+		}
+		repaired += n
+	}
+	r.txLookupVerified = verified // only after repair: failed segments are checked again by the next scan
+	return repaired, nil
+}
+
+// repair - re-generates entries of job's segment, skipping frozen blocks and blocks not reached by the stage yet.
+// Returns amount of blocks which needed repair.
+func (r *IndexRepairer) repair(ctx context.Context, job indexRepairJob) (repaired int, err error) {
+	err = r.db.Update(ctx, func(tx kv.RwTx) error {
+		var stage stages.SyncStage
+		switch job.index {
+		case DerivedIndexSenders:
+			stage = stages.Senders
+		case DerivedIndexTxLookup:
+			stage = stages.TxLookup
+		default:
+			return fmt.Errorf("unknown index: %s", job.index)
+		}
+		progress, err := stages.GetStageProgress(tx, stage)
+		if err != nil {
+			return err
+		}
+		pruned, err := stages.GetStagePruneProgress(tx, stage) // don't resurrect entries removed by --prune
+		if err != nil {
+			return err
+		}
+		genesisHash, err := rawdb.ReadCanonicalHash(tx, 0)
+		if err != nil {
+			return err
+		}
+		chainConfig, err := rawdb.ReadChainConfig(tx, genesisHash)
+		if err != nil {
+			return err
+		}
+		if chainConfig == nil {
+			return fmt.Errorf("chain config not found")
+		}
+
+		from, to := max(job.from, r.blockReader.FrozenBlocks()+1, pruned), min(job.from+indexRepairSegmentSize, progress+1)
+		for number := from; number < to; number++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			hash, err := rawdb.ReadCanonicalHash(tx, number)
+			if err != nil {
+				return err
+			}
+			if hash == (common.Hash{}) {
+				continue
+			}
+			block, senders, err := r.blockReader.BlockWithSenders(ctx, tx, hash, number)
+			if err != nil {
+				return err
+			}
+			if block == nil || block.Transactions().Len() == 0 {
+				continue
+			}
+			fixed := false
+			switch job.index {
+			case DerivedIndexSenders:
+				fixed, err = repairSenders(tx, chainConfig, block, senders)
+			case DerivedIndexTxLookup:
+				fixed, err = repairTxLookup(tx, block)
+			}
+			if err != nil {
+				return fmt.Errorf("block %d: %w", number, err)
+			}
+			if fixed {
+				repaired++
+			}
+		}
+		return nil
+	})
+	return repaired, err
+}
+
+func repairSenders(tx kv.RwTx, chainConfig *chain.Config, block *types.Block, senders []common.Address) (bool, error) {
+	txs := block.Transactions()
+	if len(senders) == len(txs) {
+		return false, nil
+	}
+	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
+	senders = make([]common.Address, len(txs))
+	for i, txn := range txs {
+		from, err := txn.Sender(*signer)
+		if err != nil {
+			return false, fmt.Errorf("recover sender of txn %d: %w", i, err)
+		}
+		senders[i] = from
+	}
+	return true, rawdb.WriteSenders(tx, block.Hash(), block.NumberU64(), senders)
+}
+
+func repairTxLookup(tx kv.RwTx, block *types.Block) (bool, error) {
+	for _, txn := range block.Transactions() {
+		n, err := rawdb.ReadTxLookupEntry(tx, txn.Hash())
+		if err != nil {
+			return false, err
+		}
+		if n == nil || *n != block.NumberU64() {
+			rawdb.WriteTxLookupEntries(tx, block)
+			return true, nil
+		}
+	}
+	return false, nil
+}
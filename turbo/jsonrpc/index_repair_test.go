@@ -0,0 +1,87 @@
+package jsonrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/dbutils"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+func TestIndexRepair(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx := context.Background()
+	r := NewIndexRepairer(m.DB, m.BlockReader, log.New())
+
+	var block *types.Block
+	err := m.DB.Update(ctx, func(tx kv.RwTx) error {
+		for number := uint64(1); block == nil; number++ {
+			b, err := m.BlockReader.BlockByNumber(ctx, tx, number)
+			require.NoError(t, err)
+			require.NotNil(t, b)
+			if b.Transactions().Len() > 0 {
+				block = b
+			}
+		}
+		if err := tx.Delete(kv.Senders, dbutils.BlockBodyKey(block.NumberU64(), block.Hash())); err != nil {
+			return err
+		}
+		for _, txn := range block.Transactions() {
+			if err := rawdb.DeleteTxLookupEntry(tx, txn.Hash()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	tx, err := m.DB.BeginRo(ctx)
+	require.NoError(t, err)
+	_, senders, err := m.BlockReader.BlockWithSenders(ctx, tx, block.Hash(), block.NumberU64())
+	require.NoError(t, err)
+	require.NoError(t, r.checkSenders(tx, block, senders))
+	tx.Rollback()
+	r.TxLookupMiss()
+	r.TxLookupMiss()
+	require.Equal(t, 2, len(r.queue))
+	require.False(t, r.Enqueue(DerivedIndexSenders, block.NumberU64()), "duplicate job must be skipped")
+
+	for len(r.queue) > 0 {
+		job := <-r.queue
+		repaired, err := r.process(ctx, job)
+		require.NoError(t, err)
+		require.Equal(t, 1, repaired, job.index)
+		r.lock.Lock()
+		delete(r.pending, job)
+		r.lock.Unlock()
+	}
+
+	// already scanned blocks are not checked again
+	repaired, err := r.scanTxLookup(ctx)
+	require.NoError(t, err)
+	require.Zero(t, repaired)
+	require.NotZero(t, r.txLookupVerified)
+
+	err = m.DB.View(ctx, func(tx kv.Tx) error {
+		senders, err := rawdb.ReadSenders(tx, block.Hash(), block.NumberU64())
+		require.NoError(t, err)
+		require.Equal(t, block.Transactions().Len(), len(senders))
+		require.NotEqual(t, common.Address{}, senders[0])
+		for _, txn := range block.Transactions() {
+			n, err := rawdb.ReadTxLookupEntry(tx, txn.Hash())
+			require.NoError(t, err)
+			require.NotNil(t, n)
+			require.Equal(t, block.NumberU64(), *n)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -399,6 +399,12 @@ func (api *OtterscanAPIImpl) GetBlockTransactions(ctx context.Context, number rp
 	for _, k := range []string{"timestamp", "miner", "baseFeePerGas"} {
 		prunedBlock[k] = getBlockRes[k]
 	}
+	// Cancun fields: absent before the fork, same as in the full block
+	for _, k := range []string{"blobGasUsed", "excessBlobGas"} {
+		if v, ok := getBlockRes[k]; ok {
+			prunedBlock[k] = v
+		}
+	}
 
 	// Crop tx input to 4bytes
 	var txs = getBlockRes["transactions"].([]interface{})
@@ -0,0 +1,344 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
+	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// QueryStrategy is the way a single step of a query plan obtains its data
+type QueryStrategy string
+
+const (
+	// QueryStrategyIndexLookup - walk inverted indices (e.g. TracesFromIdx) to find matching txNums
+	QueryStrategyIndexLookup QueryStrategy = "indexLookup"
+	// QueryStrategySnapshotScan - sequentially read every txNum of a range, blocks are taken from frozen segments where possible
+	QueryStrategySnapshotScan QueryStrategy = "snapshotScan"
+	// QueryStrategyReExecution - re-execute transactions on top of historical state to produce traces
+	QueryStrategyReExecution QueryStrategy = "reExecution"
+)
+
+// Relative costs of the unit of work done by each strategy. Numbers are not
+// wall-clock times, they only need to be comparable with each other.
+const (
+	queryCostIndexEntry  = 1   // one txNum read from an inverted index
+	queryCostSnapshotTxn = 2   // one txn read from .seg files
+	queryCostDbTxn       = 4   // one txn read from the non-frozen part of the db
+	queryCostReExecTxn   = 100 // one txn re-executed on top of history state
+
+	// queryPlannerProbeLimit caps how many index entries the planner looks at per key
+	// while estimating cardinality; bigger indices are reported as "at least" this size.
+	queryPlannerProbeLimit = 10_000
+	// on ranges smaller than this the choice of strategy doesn't matter much - probing indices costs more than it saves
+	queryPlannerMinRangeTxs = 10 * queryPlannerProbeLimit
+	// the range is scanned only if indices surely select at least this percent of its txs
+	queryPlannerScanSelectivity = 95
+)
+
+// QueryPlanStep is a single stage of a QueryPlan
+type QueryPlanStep struct {
+	Strategy      QueryStrategy `json:"strategy"`
+	Source        string        `json:"source"`
+	EstimatedRows uint64        `json:"estimatedRows"`
+	EstimatedCost uint64        `json:"estimatedCost"`
+	Capped        bool          `json:"capped,omitempty"` // EstimatedRows is a lower bound because of queryPlannerProbeLimit
+}
+
+// QueryPlan describes how a heavy archive query is going to be evaluated, see erigon_explainQuery
+type QueryPlan struct {
+	Method        string          `json:"method"`
+	FromBlock     uint64          `json:"fromBlock"`
+	ToBlock       uint64          `json:"toBlock"`
+	FromTxNum     uint64          `json:"fromTxNum"`
+	ToTxNum       uint64          `json:"toTxNum"`
+	FrozenBlocks  uint64          `json:"frozenBlocks"`
+	Strategy      QueryStrategy   `json:"strategy"`
+	EstimatedCost uint64          `json:"estimatedCost"`
+	Steps         []QueryPlanStep `json:"steps"`
+	Rejected      []QueryPlanStep `json:"rejected,omitempty"`
+	Reason        string          `json:"reason"`
+}
+
+func (p *QueryPlan) addStep(s QueryPlanStep) {
+	p.Steps = append(p.Steps, s)
+	p.EstimatedCost += s.EstimatedCost
+}
+
+// estimateIndexCardinality - counts entries of inverted index `idx` for key `k` in [fromTxNum, toTxNum), but not more than queryPlannerProbeLimit
+func estimateIndexCardinality(tx kv.TemporalTx, idx kv.InvertedIdx, k []byte, fromTxNum, toTxNum int) (n uint64, capped bool, err error) {
+	it, err := tx.IndexRange(idx, k, fromTxNum, toTxNum, order.Asc, queryPlannerProbeLimit+1)
+	if err != nil {
+		return 0, false, err
+	}
+	defer it.Close()
+	for it.HasNext() {
+		if _, err = it.Next(); err != nil {
+			return 0, false, err
+		}
+		n++
+	}
+	if n > queryPlannerProbeLimit {
+		return queryPlannerProbeLimit, true, nil
+	}
+	return n, false, nil
+}
+
+// readStep - cost of reading `txs` transactions of blocks [fromBlock, toBlock]: frozen blocks come from
+// snapshots, the rest from db. Assumes txs are evenly distributed across the range.
+func readStep(blockReader services.FullBlockReader, fromBlock, toBlock, txs uint64) QueryPlanStep {
+	step := QueryPlanStep{Strategy: QueryStrategySnapshotScan, Source: "snapshots", EstimatedRows: txs}
+	frozen := blockReader.FrozenBlocks()
+	switch {
+	case frozen < fromBlock:
+		step.Source = "db"
+		step.EstimatedCost = txs * queryCostDbTxn
+	case frozen >= toBlock:
+		step.EstimatedCost = txs * queryCostSnapshotTxn
+	default:
+		frozenTxs := txs * (frozen - fromBlock + 1) / (toBlock - fromBlock + 1)
+		step.Source = "snapshots+db"
+		step.EstimatedCost = frozenTxs*queryCostSnapshotTxn + (txs-frozenTxs)*queryCostDbTxn
+	}
+	return step
+}
+
+func reExecStep(txs uint64, capped bool) QueryPlanStep {
+	return QueryPlanStep{Strategy: QueryStrategyReExecution, Source: "history", EstimatedRows: txs, EstimatedCost: txs * queryCostReExecTxn, Capped: capped}
+}
+
+func txNumRange(tx kv.TemporalTx, fromBlock, toBlock uint64) (fromTxNum, toTxNum uint64, err error) {
+	if fromBlock > 0 {
+		if fromTxNum, err = rawdbv3.TxNums.Min(tx, fromBlock); err != nil {
+			return 0, 0, err
+		}
+	}
+	if toTxNum, err = rawdbv3.TxNums.Max(tx, toBlock); err != nil { // toBlock is an inclusive bound
+		return 0, 0, err
+	}
+	return fromTxNum, toTxNum + 1, nil
+}
+
+// needTraceFilterPlan - if trace_filter should run the planner before the query. Probing of indices is skipped
+// for a single address (there is no union/intersection overhead to save) and for small ranges.
+func needTraceFilterPlan(req TraceFilterRequest, fromTxNum, toTxNum uint64) bool {
+	if toTxNum-fromTxNum < queryPlannerMinRangeTxs {
+		return false
+	}
+	addrs := 0
+	for _, list := range [][]*common.Address{req.FromAddress, req.ToAddress} {
+		for _, addr := range list {
+			if addr != nil {
+				addrs++
+			}
+		}
+	}
+	return addrs > 1
+}
+
+// planTraceFilter - decides if trace_filter must walk TracesFromIdx/TracesToIdx or just scan the whole range.
+// For addresses which touch most of txs in the range (popular contracts) reading indices gives no
+// selectivity and only adds overhead of union/intersection of iterators.
+func planTraceFilter(tx kv.TemporalTx, blockReader services.FullBlockReader, req TraceFilterRequest, fromBlock, toBlock uint64) (*QueryPlan, error) {
+	fromTxNum, toTxNum, err := txNumRange(tx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	plan := &QueryPlan{Method: "trace_filter", FromBlock: fromBlock, ToBlock: toBlock, FromTxNum: fromTxNum, ToTxNum: toTxNum, FrozenBlocks: blockReader.FrozenBlocks()}
+	rangeTxs := toTxNum - fromTxNum
+
+	var scan []QueryPlanStep
+	scan = append(scan, readStep(blockReader, fromBlock, toBlock, rangeTxs), reExecStep(rangeTxs, false))
+
+	if len(req.FromAddress) == 0 && len(req.ToAddress) == 0 {
+		plan.Strategy = QueryStrategySnapshotScan
+		plan.Reason = "no addresses given: every txn of the range must be traced"
+		for _, s := range scan {
+			plan.addStep(s)
+		}
+		return plan, nil
+	}
+
+	estimate := func(idx kv.InvertedIdx, addrs []*common.Address) (steps []QueryPlanStep, biggest uint64, capped bool, err error) {
+		for _, addr := range addrs {
+			if addr == nil {
+				continue
+			}
+			n, c, err := estimateIndexCardinality(tx, idx, addr.Bytes(), int(fromTxNum), int(toTxNum))
+			if err != nil {
+				return nil, 0, false, err
+			}
+			steps = append(steps, QueryPlanStep{Strategy: QueryStrategyIndexLookup, Source: fmt.Sprintf("%s(%x)", idx, addr), EstimatedRows: n, EstimatedCost: n * queryCostIndexEntry, Capped: c})
+			biggest = max(biggest, n)
+			capped = capped || c
+		}
+		return steps, biggest, capped, nil
+	}
+	fromSteps, fromMax, fromCapped, err := estimate(kv.TracesFromIdx, req.FromAddress)
+	if err != nil {
+		return nil, err
+	}
+	toSteps, toMax, toCapped, err := estimate(kv.TracesToIdx, req.ToAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// indices of different addresses may overlap, so only lower bounds are known: the union is not smaller than
+	// the biggest index, the intersection - than the part of both biggest indices which can't fit into the range apart
+	matched, capped := min(max(fromMax, toMax), rangeTxs), fromCapped || toCapped
+	if req.Mode == TraceFilterModeIntersection {
+		matched = 0
+		if fromMax+toMax > rangeTxs {
+			matched = fromMax + toMax - rangeTxs
+		}
+	}
+
+	var index []QueryPlanStep
+	index = append(index, fromSteps...)
+	index = append(index, toSteps...)
+	index = append(index, readStep(blockReader, fromBlock, toBlock, matched), reExecStep(matched, capped))
+
+	chosen, rejected := index, scan
+	plan.Strategy = QueryStrategyIndexLookup
+	plan.Reason = fmt.Sprintf("indices select at least %d of %d txs", matched, rangeTxs)
+	// the index plan is costed by a lower bound, so costs of both plans are not compared: scan only if it surely
+	// re-executes almost nothing extra. Capped indices give no such guarantee
+	if !capped && matched*100 >= rangeTxs*queryPlannerScanSelectivity {
+		chosen, rejected = scan, index
+		plan.Strategy = QueryStrategySnapshotScan
+		plan.Reason = fmt.Sprintf("addresses touch at least %d of %d txs: indices give no selectivity", matched, rangeTxs)
+	}
+	for _, s := range chosen {
+		plan.addStep(s)
+	}
+	plan.Rejected = rejected
+	return plan, nil
+}
+
+// planBlockTrace - trace_block has no choice: all txs of the block are re-executed
+func planBlockTrace(tx kv.TemporalTx, blockReader services.FullBlockReader, method string, blockNum uint64) (*QueryPlan, error) {
+	fromTxNum, toTxNum, err := txNumRange(tx, blockNum, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	plan := &QueryPlan{Method: method, FromBlock: blockNum, ToBlock: blockNum, FromTxNum: fromTxNum, ToTxNum: toTxNum, FrozenBlocks: blockReader.FrozenBlocks()}
+	plan.Strategy = QueryStrategyReExecution
+	plan.Reason = "state of every txn is needed to trace the block"
+	txs := toTxNum - fromTxNum
+	plan.addStep(readStep(blockReader, blockNum, blockNum, txs))
+	plan.addStep(reExecStep(txs, false))
+	return plan, nil
+}
+
+// planOtsSearch - ots_searchTransactionsBefore/After walk TracesFromIdx/TracesToIdx from the given block
+// and re-execute matching txs until page is full
+func planOtsSearch(tx kv.TemporalTx, blockReader services.FullBlockReader, method string, addr common.Address, blockNum uint64, pageSize uint16, backward bool) (*QueryPlan, error) {
+	latest, _, err := rawdbv3.TxNums.Last(tx)
+	if err != nil {
+		return nil, err
+	}
+	fromBlock, toBlock := uint64(0), latest
+	switch {
+	case blockNum == 0:
+	case backward:
+		toBlock = blockNum - 1
+	default:
+		fromBlock = blockNum + 1
+	}
+	if fromBlock > toBlock {
+		fromBlock = toBlock
+	}
+	fromTxNum, toTxNum, err := txNumRange(tx, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	plan := &QueryPlan{Method: method, FromBlock: fromBlock, ToBlock: toBlock, FromTxNum: fromTxNum, ToTxNum: toTxNum, FrozenBlocks: blockReader.FrozenBlocks()}
+	plan.Strategy = QueryStrategyIndexLookup
+	plan.Reason = fmt.Sprintf("paginated search, at most %d matching txs are re-executed", pageSize)
+
+	var total uint64
+	var capped bool
+	for _, idx := range []kv.InvertedIdx{kv.TracesFromIdx, kv.TracesToIdx} {
+		n, c, err := estimateIndexCardinality(tx, idx, addr[:], int(fromTxNum), int(toTxNum))
+		if err != nil {
+			return nil, err
+		}
+		plan.addStep(QueryPlanStep{Strategy: QueryStrategyIndexLookup, Source: fmt.Sprintf("%s(%x)", idx, addr), EstimatedRows: n, EstimatedCost: n * queryCostIndexEntry, Capped: c})
+		total += n
+		capped = capped || c
+	}
+	matched := min(total, uint64(pageSize))
+	plan.addStep(readStep(blockReader, fromBlock, toBlock, matched))
+	plan.addStep(reExecStep(matched, false))
+	if capped {
+		plan.Reason += "; index is bigger than the planner probe limit"
+	}
+	return plan, nil
+}
+
+// explainQuery - builds the QueryPlan of `method` called with positional `params`
+func (api *BaseAPI) explainQuery(tx kv.TemporalTx, method string, params []json.RawMessage) (*QueryPlan, error) {
+	switch method {
+	case "trace_filter":
+		var req TraceFilterRequest
+		if err := decodeExplainParams(params, &req); err != nil {
+			return nil, err
+		}
+		var fromBlock, toBlock uint64
+		if req.FromBlock != nil {
+			fromBlock = uint64(*req.FromBlock)
+		}
+		if req.ToBlock == nil {
+			headNumber := rawdb.ReadHeaderNumber(tx, rawdb.ReadHeadHeaderHash(tx))
+			if headNumber == nil {
+				return nil, fmt.Errorf("head header not found")
+			}
+			toBlock = *headNumber
+		} else {
+			toBlock = uint64(*req.ToBlock)
+		}
+		if fromBlock > toBlock {
+			return nil, fmt.Errorf("invalid parameters: fromBlock cannot be greater than toBlock")
+		}
+		return planTraceFilter(tx, api._blockReader, req, fromBlock, toBlock)
+	case "trace_block":
+		var blockNr rpc.BlockNumber
+		if err := decodeExplainParams(params, &blockNr); err != nil {
+			return nil, err
+		}
+		blockNum, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(blockNr), tx, api.filters)
+		if err != nil {
+			return nil, err
+		}
+		return planBlockTrace(tx, api._blockReader, method, blockNum)
+	case "ots_searchTransactionsBefore", "ots_searchTransactionsAfter":
+		var addr common.Address
+		var blockNum uint64
+		var pageSize uint16
+		if err := decodeExplainParams(params, &addr, &blockNum, &pageSize); err != nil {
+			return nil, err
+		}
+		return planOtsSearch(tx, api._blockReader, method, addr, blockNum, pageSize, method == "ots_searchTransactionsBefore")
+	default:
+		return nil, fmt.Errorf("explain is not supported for method %s", method)
+	}
+}
+
+func decodeExplainParams(params []json.RawMessage, args ...interface{}) error {
+	if len(params) < len(args) {
+		return fmt.Errorf("missing value for required argument %d", len(params))
+	}
+	for i, arg := range args {
+		if err := json.Unmarshal(params[i], arg); err != nil {
+			return fmt.Errorf("invalid argument %d: %w", i, err)
+		}
+	}
+	return nil
+}
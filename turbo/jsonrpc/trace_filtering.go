@@ -333,6 +333,17 @@ func (api *TraceAPIImpl) filterV3(ctx context.Context, dbtx kv.TemporalTx, fromB
 	if err != nil {
 		return err
 	}
+	if (len(fromAddresses) > 0 || len(toAddresses) > 0) && needTraceFilterPlan(req, fromTxNum, toTxNum) {
+		plan, err := planTraceFilter(dbtx, api._blockReader, req, fromBlock, toBlock)
+		if err != nil {
+			return err
+		}
+		if plan.Strategy == QueryStrategySnapshotScan {
+			// addresses are still checked by filterTrace below
+			allTxs = iter.Range[uint64](fromTxNum, toTxNum)
+		}
+		log.Debug("[rpc] trace_filter plan", "strategy", plan.Strategy, "cost", plan.EstimatedCost, "reason", plan.Reason)
+	}
 	it := rawdbv3.TxNums2BlockNums(dbtx, allTxs, order.Asc)
 	defer it.Close()
 
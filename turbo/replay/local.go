@@ -0,0 +1,168 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/holiman/uint256"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+// LocalExecutor executes transactions in-process, with given vm.Config, on top of historical state of db.
+// Not safe for concurrent use.
+type LocalExecutor struct {
+	db          kv.RoDB // must be temporal: history is read by txNum
+	blockReader services.FullBlockReader
+	engine      consensus.EngineReader // nil - block author is header.Coinbase
+	chainConfig *chain.Config
+	vmConfig    vm.Config
+
+	block *types.Block // last used, all transactions of a block are replayed in a row
+}
+
+func NewLocalExecutor(db kv.RoDB, blockReader services.FullBlockReader, engine consensus.EngineReader, chainConfig *chain.Config, vmConfig vm.Config) *LocalExecutor {
+	return &LocalExecutor{db: db, blockReader: blockReader, engine: engine, chainConfig: chainConfig, vmConfig: vmConfig}
+}
+
+func (e *LocalExecutor) BlockTxs(ctx context.Context, blockNum uint64) ([]libcommon.Hash, error) {
+	tx, err := e.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	block, err := e.readBlock(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]libcommon.Hash, 0, block.Transactions().Len())
+	for _, txn := range block.Transactions() {
+		hashes = append(hashes, txn.Hash())
+	}
+	return hashes, nil
+}
+
+func (e *LocalExecutor) ReplayTx(ctx context.Context, blockNum uint64, txIndex int, txHash libcommon.Hash) (*TxResult, error) {
+	tx, err := e.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	block, err := e.readBlock(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if txIndex >= block.Transactions().Len() || block.Transactions()[txIndex].Hash() != txHash {
+		return nil, fmt.Errorf("transaction %x not found at index %d", txHash, txIndex)
+	}
+
+	msg, blockCtx, txCtx, ibs, _, err := transactions.ComputeTxEnv(ctx, e.engine, block, e.chainConfig, e.blockReader, tx, txIndex)
+	if err != nil {
+		return nil, err
+	}
+	evm := vm.NewEVM(blockCtx, txCtx, ibs, e.chainConfig, e.vmConfig)
+	gp := new(core.GasPool).AddGas(msg.Gas()).AddBlobGas(msg.BlobGas())
+	execResult, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+	if err != nil {
+		return &TxResult{Err: err.Error()}, nil
+	}
+
+	res := &TxResult{
+		GasUsed:    execResult.UsedGas,
+		Failed:     execResult.Failed(),
+		ReturnData: libcommon.CopyBytes(execResult.ReturnData),
+	}
+	for _, l := range ibs.GetLogs(txHash) {
+		res.Logs = append(res.Logs, &Log{Address: l.Address, Topics: l.Topics, Data: l.Data})
+	}
+	w := &diffWriter{state: map[libcommon.Address]*AccountDiff{}}
+	if err := ibs.FinalizeTx(evm.ChainRules(), w); err != nil {
+		return nil, err
+	}
+	res.State = w.state
+	return res, nil
+}
+
+func (e *LocalExecutor) readBlock(ctx context.Context, tx kv.Tx, blockNum uint64) (*types.Block, error) {
+	if e.block != nil && e.block.NumberU64() == blockNum {
+		return e.block, nil
+	}
+	block, err := e.blockReader.BlockByNumber(ctx, tx, blockNum)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNum)
+	}
+	e.block = block
+	return block, nil
+}
+
+// diffWriter implements state.StateWriter: collects changes of a single transaction, skips no-op writes
+// (IntraBlockState writes all touched accounts)
+type diffWriter struct {
+	state map[libcommon.Address]*AccountDiff
+}
+
+func (w *diffWriter) account(address libcommon.Address) *AccountDiff {
+	acc, ok := w.state[address]
+	if !ok {
+		acc = &AccountDiff{}
+		w.state[address] = acc
+	}
+	return acc
+}
+
+func (w *diffWriter) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	if original == nil {
+		original = &accounts.Account{}
+	}
+	if !original.Balance.Eq(&account.Balance) {
+		w.account(address).Balance = account.Balance.Clone()
+	}
+	if original.Nonce != account.Nonce {
+		nonce := account.Nonce
+		w.account(address).Nonce = &nonce
+	}
+	return nil
+}
+
+func (w *diffWriter) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	w.account(address).Code = libcommon.CopyBytes(code)
+	return nil
+}
+
+func (w *diffWriter) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	if original == nil || !original.Initialised { // didn't exist before the transaction
+		delete(w.state, address)
+		return nil
+	}
+	w.state[address] = &AccountDiff{Deleted: true}
+	return nil
+}
+
+func (w *diffWriter) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	if original.Eq(value) {
+		return nil
+	}
+	acc := w.account(address)
+	if acc.Storage == nil {
+		acc.Storage = map[libcommon.Hash]libcommon.Hash{}
+	}
+	acc.Storage[*key] = value.Bytes32()
+	return nil
+}
+
+func (w *diffWriter) CreateContract(address libcommon.Address) error {
+	return nil
+}
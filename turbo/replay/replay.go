@@ -0,0 +1,253 @@
+// Package replay re-executes historical transactions through 2 EVM implementations (Executor) and reports
+// divergences in gas, status, return data, logs and state changes. Used to validate EVM changes (optimizations,
+// rewrites) against mainnet history: in-process with 2 different vm.Config (LocalExecutor) or against
+// 2 running binaries (RpcExecutor).
+//
+// Every transaction is executed on top of the historical state right before it (not on top of the other
+// executor's results) - so a divergence in one transaction doesn't cascade to the following ones and the
+// same range always produces the same report.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+// Executor re-executes historical transactions. Implementations must produce comparable TxResult:
+// only state which was changed by the transaction, with post-transaction values.
+type Executor interface {
+	// BlockTxs - hashes of block transactions, in execution order
+	BlockTxs(ctx context.Context, blockNum uint64) ([]libcommon.Hash, error)
+	// ReplayTx executes the transaction on top of the state right before it. Transaction which can't be
+	// applied (consensus error) is reported by TxResult.Err, error is returned only if execution was not possible.
+	ReplayTx(ctx context.Context, blockNum uint64, txIndex int, txHash libcommon.Hash) (*TxResult, error)
+}
+
+// TxResult - outcome of a single transaction
+type TxResult struct {
+	Err        string // consensus error: transaction was not applied, other fields are empty
+	GasUsed    uint64
+	Failed     bool
+	ReturnData []byte
+	Logs       []*Log
+	State      map[libcommon.Address]*AccountDiff // only changed accounts
+}
+
+type Log struct {
+	Address libcommon.Address
+	Topics  []libcommon.Hash
+	Data    []byte
+}
+
+// AccountDiff - post-transaction values of changed fields, nil - unchanged
+type AccountDiff struct {
+	Deleted bool
+	Balance *uint256.Int
+	Nonce   *uint64
+	Code    []byte
+	Storage map[libcommon.Hash]libcommon.Hash
+}
+
+// Divergence - field of a transaction result which differs between executors
+type Divergence struct {
+	BlockNum uint64         `json:"blockNumber"`
+	TxIndex  int            `json:"transactionIndex"`
+	TxHash   libcommon.Hash `json:"transactionHash"`
+	Field    string         `json:"field"` // for example: gasUsed, logs[1].data, state.0x...balance
+	A        string         `json:"a"`
+	B        string         `json:"b"`
+}
+
+type Stats struct {
+	Blocks      uint64
+	Txs         uint64
+	DivergedTxs uint64
+}
+
+// Run replays blocks [fromBlock, toBlock] by both executors and calls onDivergence for every divergence,
+// in deterministic order. Stops at first error of executors or of onDivergence.
+func Run(ctx context.Context, a, b Executor, fromBlock, toBlock uint64, onDivergence func(Divergence) error, logger log.Logger) (Stats, error) {
+	var stats Stats
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	for blockNum := fromBlock; blockNum <= toBlock; blockNum++ {
+		select {
+		case <-ctx.Done():
+			return stats, ctx.Err()
+		case <-logEvery.C:
+			logger.Info("[replay] progress", "block", blockNum, "txs", stats.Txs, "diverged", stats.DivergedTxs)
+		default:
+		}
+
+		txsA, err := a.BlockTxs(ctx, blockNum)
+		if err != nil {
+			return stats, fmt.Errorf("block %d: a: %w", blockNum, err)
+		}
+		txsB, err := b.BlockTxs(ctx, blockNum)
+		if err != nil {
+			return stats, fmt.Errorf("block %d: b: %w", blockNum, err)
+		}
+		if !equalHashes(txsA, txsB) { // executors see different chains - nothing to compare
+			return stats, fmt.Errorf("block %d: executors have different transactions: %d vs %d", blockNum, len(txsA), len(txsB))
+		}
+
+		for txIndex, txHash := range txsA {
+			resA, err := a.ReplayTx(ctx, blockNum, txIndex, txHash)
+			if err != nil {
+				return stats, fmt.Errorf("block %d, tx %d (%x): a: %w", blockNum, txIndex, txHash, err)
+			}
+			resB, err := b.ReplayTx(ctx, blockNum, txIndex, txHash)
+			if err != nil {
+				return stats, fmt.Errorf("block %d, tx %d (%x): b: %w", blockNum, txIndex, txHash, err)
+			}
+			stats.Txs++
+
+			divergences := Compare(resA, resB)
+			if len(divergences) > 0 {
+				stats.DivergedTxs++
+			}
+			for _, d := range divergences {
+				d.BlockNum, d.TxIndex, d.TxHash = blockNum, txIndex, txHash
+				if err := onDivergence(d); err != nil {
+					return stats, err
+				}
+			}
+		}
+		stats.Blocks++
+	}
+	return stats, nil
+}
+
+// Compare returns differences between results, BlockNum/TxIndex/TxHash are not filled
+func Compare(a, b *TxResult) []Divergence {
+	var res []Divergence
+	diff := func(field, valA, valB string) {
+		if valA != valB {
+			res = append(res, Divergence{Field: field, A: valA, B: valB})
+		}
+	}
+
+	diff("error", a.Err, b.Err)
+	diff("gasUsed", strconv.FormatUint(a.GasUsed, 10), strconv.FormatUint(b.GasUsed, 10))
+	diff("failed", strconv.FormatBool(a.Failed), strconv.FormatBool(b.Failed))
+	diff("returnData", hexutility.Encode(a.ReturnData), hexutility.Encode(b.ReturnData))
+
+	diff("logs.len", strconv.Itoa(len(a.Logs)), strconv.Itoa(len(b.Logs)))
+	for i := 0; i < min(len(a.Logs), len(b.Logs)); i++ {
+		logA, logB := a.Logs[i], b.Logs[i]
+		diff(fmt.Sprintf("logs[%d].address", i), logA.Address.Hex(), logB.Address.Hex())
+		diff(fmt.Sprintf("logs[%d].topics", i), fmt.Sprint(logA.Topics), fmt.Sprint(logB.Topics))
+		diff(fmt.Sprintf("logs[%d].data", i), hexutility.Encode(logA.Data), hexutility.Encode(logB.Data))
+	}
+
+	for _, addr := range sortedAddresses(a.State, b.State) {
+		accA, accB := a.State[addr], b.State[addr]
+		prefix := "state." + addr.Hex()
+		if accA == nil || accB == nil {
+			diff(prefix, accountSummary(accA), accountSummary(accB))
+			continue
+		}
+		diff(prefix+".deleted", strconv.FormatBool(accA.Deleted), strconv.FormatBool(accB.Deleted))
+		diff(prefix+".balance", formatBalance(accA.Balance), formatBalance(accB.Balance))
+		diff(prefix+".nonce", formatNonce(accA.Nonce), formatNonce(accB.Nonce))
+		diff(prefix+".code", formatCode(accA.Code), formatCode(accB.Code))
+		for _, key := range sortedKeys(accA.Storage, accB.Storage) {
+			diff(prefix+".storage."+key.Hex(), formatSlot(accA.Storage, key), formatSlot(accB.Storage, key))
+		}
+	}
+	return res
+}
+
+func equalHashes(a, b []libcommon.Hash) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedAddresses(a, b map[libcommon.Address]*AccountDiff) []libcommon.Address {
+	addrs := make([]libcommon.Address, 0, len(a)+len(b))
+	for addr := range a {
+		addrs = append(addrs, addr)
+	}
+	for addr := range b {
+		if _, ok := a[addr]; !ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return bytes.Compare(addrs[i][:], addrs[j][:]) < 0 })
+	return addrs
+}
+
+func sortedKeys(a, b map[libcommon.Hash]libcommon.Hash) []libcommon.Hash {
+	keys := make([]libcommon.Hash, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+	return keys
+}
+
+func accountSummary(acc *AccountDiff) string {
+	switch {
+	case acc == nil:
+		return "unchanged"
+	case acc.Deleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("balance=%s nonce=%s code=%s storage=%d", formatBalance(acc.Balance), formatNonce(acc.Nonce), formatCode(acc.Code), len(acc.Storage))
+	}
+}
+
+func formatBalance(balance *uint256.Int) string {
+	if balance == nil {
+		return "unchanged"
+	}
+	return balance.Dec()
+}
+
+func formatNonce(nonce *uint64) string {
+	if nonce == nil {
+		return "unchanged"
+	}
+	return strconv.FormatUint(*nonce, 10)
+}
+
+// formatCode - code may be big, hash is enough to see the difference
+func formatCode(code []byte) string {
+	if code == nil {
+		return "unchanged"
+	}
+	return fmt.Sprintf("len=%d hash=%x", len(code), crypto.Keccak256(code))
+}
+
+func formatSlot(storage map[libcommon.Hash]libcommon.Hash, key libcommon.Hash) string {
+	val, ok := storage[key]
+	if !ok {
+		return "unchanged"
+	}
+	return val.Hex()
+}
@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/vm"
+	_ "github.com/ledgerwatch/erigon/eth/tracers/native"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/jsonrpc"
+)
+
+func TestCompare(t *testing.T) {
+	addr1 := libcommon.HexToAddress("0x01")
+	addr2 := libcommon.HexToAddress("0x02")
+	nonce := uint64(1)
+	a := &TxResult{
+		GasUsed: 21000,
+		Logs:    []*Log{{Address: addr1, Data: []byte{1}}},
+		State: map[libcommon.Address]*AccountDiff{
+			addr2: {Balance: uint256.NewInt(1), Storage: map[libcommon.Hash]libcommon.Hash{{1}: {2}}},
+			addr1: {Nonce: &nonce},
+		},
+	}
+	require.Empty(t, Compare(a, a))
+
+	b := &TxResult{
+		GasUsed: 21001,
+		Logs:    []*Log{{Address: addr1, Data: []byte{2}}},
+		State: map[libcommon.Address]*AccountDiff{
+			addr2: {Balance: uint256.NewInt(2)},
+		},
+	}
+	fields := func(divergences []Divergence) (res []string) {
+		for _, d := range divergences {
+			res = append(res, d.Field)
+		}
+		return res
+	}
+	require.Equal(t, []string{
+		"gasUsed",
+		"logs[0].data",
+		"state." + addr1.Hex(),
+		"state." + addr2.Hex() + ".balance",
+		"state." + addr2.Hex() + ".storage." + libcommon.Hash{1}.Hex(),
+	}, fields(Compare(a, b)))
+
+	divergences := Compare(a, b)
+	require.Equal(t, "21000", divergences[0].A)
+	require.Equal(t, "21001", divergences[0].B)
+	require.Equal(t, "unchanged", divergences[2].B)
+}
+
+func TestRun(t *testing.T) {
+	m, chain, _ := rpcdaemontest.CreateTestSentry(t)
+	logger := log.New()
+	toBlock := chain.TopBlock.NumberU64()
+
+	agg := m.HistoryV3Components()
+	base := jsonrpc.NewBaseApi(nil, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	srv := rpc.NewServer(50, false, false, true, logger, 0)
+	require.NoError(t, srv.RegisterName("eth", jsonrpc.NewEthAPI(base, m.DB, nil, nil, nil, 5000000, 100_000, false, 100_000, 128, logger)))
+	require.NoError(t, srv.RegisterName("debug", jsonrpc.NewPrivateDebugAPI(base, m.DB, 0)))
+	t.Cleanup(srv.Stop)
+	client := rpc.DialInProc(srv, logger)
+	t.Cleanup(client.Close)
+
+	noDivergences := func(d Divergence) error {
+		t.Errorf("unexpected divergence: %+v", d)
+		return nil
+	}
+	local := NewLocalExecutor(m.DB, m.BlockReader, m.Engine, m.ChainConfig, vm.Config{})
+
+	t.Run("two configs", func(t *testing.T) {
+		skipAnalysis := NewLocalExecutor(m.DB, m.BlockReader, m.Engine, m.ChainConfig, vm.Config{SkipAnalysis: true})
+		stats, err := Run(context.Background(), local, skipAnalysis, 1, toBlock, noDivergences, logger)
+		require.NoError(t, err)
+		require.Equal(t, toBlock, stats.Blocks)
+		require.NotZero(t, stats.Txs)
+		require.Zero(t, stats.DivergedTxs)
+	})
+
+	t.Run("local and rpc", func(t *testing.T) {
+		stats, err := Run(context.Background(), local, NewRpcExecutor(client), 1, toBlock, noDivergences, logger)
+		require.NoError(t, err)
+		require.NotZero(t, stats.Txs)
+		require.Zero(t, stats.DivergedTxs)
+	})
+}
+
+type fakeDebugAPI struct {
+	err error
+}
+
+func (api *fakeDebugAPI) TraceTransaction(ctx context.Context, hash libcommon.Hash, config map[string]interface{}) (interface{}, error) {
+	return nil, api.err
+}
+
+func TestRpcExecutorErrors(t *testing.T) {
+	logger := log.New()
+	replayTx := func(t *testing.T, debugAPI *fakeDebugAPI) (*TxResult, error) {
+		srv := rpc.NewServer(50, false, false, true, logger, 0)
+		if debugAPI != nil {
+			require.NoError(t, srv.RegisterName("debug", debugAPI))
+		}
+		t.Cleanup(srv.Stop)
+		client := rpc.DialInProc(srv, logger)
+		t.Cleanup(client.Close)
+		return NewRpcExecutor(client).ReplayTx(context.Background(), 1, 0, libcommon.Hash{1})
+	}
+
+	t.Run("not applied", func(t *testing.T) {
+		res, err := replayTx(t, &fakeDebugAPI{err: errors.New("tracing failed: nonce too high")})
+		require.NoError(t, err)
+		require.Equal(t, "nonce too high", res.Err)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		_, err := replayTx(t, &fakeDebugAPI{err: errors.New("execution timeout")})
+		require.ErrorContains(t, err, "execution timeout")
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := replayTx(t, &fakeDebugAPI{})
+		require.ErrorContains(t, err, "not found by node")
+	})
+	t.Run("no debug namespace", func(t *testing.T) {
+		_, err := replayTx(t, nil)
+		require.ErrorContains(t, err, "does not exist")
+	})
+}
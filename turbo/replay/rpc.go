@@ -0,0 +1,153 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/holiman/uint256"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutil"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// RpcExecutor executes transactions by a running node: debug_traceTransaction with built-in
+// callTracer (gas, status, logs) and prestateTracer in diff mode (state changes).
+type RpcExecutor struct {
+	client *rpc.Client
+}
+
+func NewRpcExecutor(client *rpc.Client) *RpcExecutor {
+	return &RpcExecutor{client: client}
+}
+
+func (e *RpcExecutor) BlockTxs(ctx context.Context, blockNum uint64) ([]libcommon.Hash, error) {
+	var block *struct {
+		Transactions []libcommon.Hash `json:"transactions"`
+	}
+	if err := e.client.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.Uint64(blockNum), false); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNum)
+	}
+	return block.Transactions, nil
+}
+
+type rpcCallLog struct {
+	Index   uint64            `json:"index"`
+	Address libcommon.Address `json:"address"`
+	Topics  []libcommon.Hash  `json:"topics"`
+	Data    hexutility.Bytes  `json:"data"`
+}
+
+type rpcCallFrame struct {
+	GasUsed hexutil.Uint64   `json:"gasUsed"`
+	Output  hexutility.Bytes `json:"output"`
+	Error   string           `json:"error"`
+	Calls   []rpcCallFrame   `json:"calls"`
+	Logs    []rpcCallLog     `json:"logs"`
+}
+
+func (f *rpcCallFrame) collectLogs(logs []rpcCallLog) []rpcCallLog {
+	logs = append(logs, f.Logs...)
+	for i := range f.Calls {
+		logs = f.Calls[i].collectLogs(logs)
+	}
+	return logs
+}
+
+type rpcAccount struct {
+	Balance *hexutil.Big                      `json:"balance"`
+	Code    *hexutility.Bytes                 `json:"code"`
+	Nonce   *uint64                           `json:"nonce"`
+	Storage map[libcommon.Hash]libcommon.Hash `json:"storage"`
+}
+
+type rpcStateDiff struct {
+	Pre  map[libcommon.Address]*rpcAccount `json:"pre"`
+	Post map[libcommon.Address]*rpcAccount `json:"post"`
+}
+
+func (e *RpcExecutor) ReplayTx(ctx context.Context, blockNum uint64, txIndex int, txHash libcommon.Hash) (*TxResult, error) {
+	var frame *rpcCallFrame
+	if err := e.trace(ctx, txHash, "callTracer", map[string]interface{}{"withLog": true}, &frame); err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) && strings.HasPrefix(rpcErr.Error(), applyMessageErrPrefix) { // node refused to apply the transaction
+			return &TxResult{Err: strings.TrimPrefix(rpcErr.Error(), applyMessageErrPrefix)}, nil
+		}
+		return nil, err // method not found, timeout, etc. - node can't replay, not a divergence
+	}
+	var stateDiff *rpcStateDiff
+	if err := e.trace(ctx, txHash, "prestateTracer", map[string]interface{}{"diffMode": true}, &stateDiff); err != nil {
+		return nil, err
+	}
+	if frame == nil || stateDiff == nil {
+		return nil, fmt.Errorf("transaction %x not found by node", txHash)
+	}
+
+	res := &TxResult{
+		GasUsed:    uint64(frame.GasUsed),
+		Failed:     frame.Error != "",
+		ReturnData: frame.Output,
+		State:      stateDiff.toState(),
+	}
+	logs := frame.collectLogs(nil)
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Index < logs[j].Index })
+	for _, l := range logs {
+		res.Logs = append(res.Logs, &Log{Address: l.Address, Topics: l.Topics, Data: l.Data})
+	}
+	return res, nil
+}
+
+// applyMessageErrPrefix - debug_traceTransaction (of Erigon and Geth) wraps errors of core.ApplyMessage by it,
+// the rest is the same as TxResult.Err of LocalExecutor
+const applyMessageErrPrefix = "tracing failed: "
+
+func (e *RpcExecutor) trace(ctx context.Context, txHash libcommon.Hash, tracer string, tracerConfig map[string]interface{}, result interface{}) error {
+	return e.client.CallContext(ctx, result, "debug_traceTransaction", txHash, map[string]interface{}{
+		"tracer":       tracer,
+		"tracerConfig": tracerConfig,
+	})
+}
+
+// toState converts prestateTracer diff to TxResult.State. Pre has only changed accounts and slots:
+// account which is only in pre was deleted, slot which is only in pre was set to zero.
+func (d *rpcStateDiff) toState() map[libcommon.Address]*AccountDiff {
+	state := make(map[libcommon.Address]*AccountDiff, len(d.Post))
+	for addr := range d.Pre {
+		if _, ok := d.Post[addr]; !ok {
+			state[addr] = &AccountDiff{Deleted: true}
+		}
+	}
+	for addr, post := range d.Post {
+		acc := &AccountDiff{Nonce: post.Nonce}
+		if post.Balance != nil {
+			acc.Balance, _ = uint256.FromBig(post.Balance.ToInt())
+		}
+		if post.Code != nil {
+			acc.Code = *post.Code
+		}
+		if pre, ok := d.Pre[addr]; ok {
+			for key := range pre.Storage {
+				if acc.Storage == nil {
+					acc.Storage = map[libcommon.Hash]libcommon.Hash{}
+				}
+				acc.Storage[key] = libcommon.Hash{}
+			}
+		}
+		for key, val := range post.Storage {
+			if acc.Storage == nil {
+				acc.Storage = map[libcommon.Hash]libcommon.Hash{}
+			}
+			acc.Storage[key] = val
+		}
+		state[addr] = acc
+	}
+	return state
+}
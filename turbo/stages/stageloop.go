@@ -14,6 +14,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/datadir"
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
+	"github.com/ledgerwatch/erigon-lib/common/disk"
 	proto_downloader "github.com/ledgerwatch/erigon-lib/gointerfaces/downloaderproto"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/membatchwithdb"
@@ -52,9 +53,11 @@ func StageLoop(
 	logger log.Logger,
 	blockReader services.FullBlockReader,
 	hook *Hook,
+	budget *disk.Budget,
 ) {
 	defer close(waitForDone)
 	initialCycle := true
+	budgetLevel, filesStopped := disk.BudgetOk, false
 
 	for {
 		start := time.Now()
@@ -66,6 +69,30 @@ func StageLoop(
 			// continue
 		}
 
+		// Disk budget (downloads are paused by its listener): when the level rises - prune once, time-boxed as usual.
+		// When exceeded - stop the Snapshots stage, which retires blocks into new files (but not in the initial
+		// cycle - it downloads them). Execution of new blocks goes on.
+		level := budget.Level()
+		if stopFiles := level == disk.BudgetExceeded && !initialCycle; stopFiles != filesStopped {
+			if stopFiles {
+				logger.Error("[disk] datadir max size exceeded: snapshot downloads and files production are stopped, free disk space or increase --datadir.max-size", "used", libcommon.ByteCount(budget.Used()), "max", libcommon.ByteCount(budget.MaxSize()))
+				sync.DisableStages(stages.Snapshots)
+			} else {
+				logger.Info("[disk] datadir is within max size: snapshots stage is resumed", "used", libcommon.ByteCount(budget.Used()), "max", libcommon.ByteCount(budget.MaxSize()))
+				sync.EnableStages(stages.Snapshots)
+			}
+			filesStopped = stopFiles
+		}
+		if level > budgetLevel {
+			if err := sync.RunPrune(db, nil, false); err != nil {
+				if errors.Is(err, libcommon.ErrStopped) || errors.Is(err, context.Canceled) {
+					return
+				}
+				logger.Error("Staged Sync: prune", "err", err)
+			}
+		}
+		budgetLevel = level
+
 		// Estimate the current top height seen from the peer
 		err := StageLoopIteration(ctx, db, wrap.TxContainer{}, sync, initialCycle, false, logger, blockReader, hook)
 